@@ -1,10 +1,13 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/gorcon/rcon-cli/internal/executor"
+	"golang.org/x/term"
 )
 
 // Version displays service version in semantic versioning (http://semver.org/).
@@ -12,13 +15,23 @@ import (
 var Version = "develop"
 
 func main() {
-	exec := executor.NewExecutor(os.Stdin, os.Stdout, Version)
+	// Output is buffered when it is not a terminal, e.g. in a pipeline.
+	// Use --disable-buffering to flush it after each response.
+	var stdout io.Writer = os.Stdout
 
-	if err := exec.Run(os.Args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		exec.Close()
-		os.Exit(1)
+	buffered := bufio.NewWriter(os.Stdout)
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		stdout = buffered
 	}
 
+	exec := executor.NewExecutor(os.Stdin, stdout, Version)
+
+	err := exec.Run(os.Args)
 	exec.Close()
+	buffered.Flush()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
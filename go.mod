@@ -3,12 +3,19 @@ module github.com/gorcon/rcon-cli
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/chzyer/readline v1.5.1
+	github.com/d5/tengo/v2 v2.17.0
+	github.com/expr-lang/expr v1.17.8
+	github.com/fsnotify/fsnotify v1.7.0
 	github.com/gorcon/rcon v1.3.5
 	github.com/gorcon/telnet v1.2.3
 	github.com/gorcon/websocket v1.1.3
 	github.com/gorilla/websocket v1.5.1
 	github.com/stretchr/testify v1.7.1
 	github.com/urfave/cli/v2 v2.27.1
+	golang.org/x/term v0.16.0
+	golang.org/x/text v0.14.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -19,4 +26,5 @@ require (
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/xrash/smetrics v0.0.0-20231213231151-1d8dd44e695e // indirect
 	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
 )
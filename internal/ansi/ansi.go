@@ -0,0 +1,36 @@
+// Package ansi removes ANSI escape sequences from text.
+package ansi
+
+import (
+	"io"
+	"regexp"
+)
+
+// pattern matches CSI (colors, cursor movements) and OSC (titles, links)
+// escape sequences.
+var pattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// Strip returns s without ANSI escape sequences.
+func Strip(s string) string {
+	return pattern.ReplaceAllString(s, "")
+}
+
+// Writer strips ANSI escape sequences before writing to the underlying writer.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter creates a new Writer.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes p without ANSI escape sequences to the underlying writer.
+// It returns len(p) on success, so the stripped bytes are reported as written.
+func (w *Writer) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(pattern.ReplaceAll(p, nil)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
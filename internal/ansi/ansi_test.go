@@ -0,0 +1,32 @@
+package ansi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrip(t *testing.T) {
+	t.Run("colors", func(t *testing.T) {
+		assert.Equal(t, "Players connected (2)", ansi.Strip("\x1b[1;32mPlayers\x1b[0m connected (2)"))
+	})
+
+	t.Run("cursor and title", func(t *testing.T) {
+		assert.Equal(t, "status", ansi.Strip("\x1b]0;rcon\x07\x1b[2Kstatus"))
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		assert.Equal(t, "[chat] hello", ansi.Strip("[chat] hello"))
+	})
+}
+
+func TestWriter(t *testing.T) {
+	buf := bytes.Buffer{}
+
+	n, err := ansi.NewWriter(&buf).Write([]byte("\x1b[31merror\x1b[0m"))
+	assert.NoError(t, err)
+	assert.Equal(t, 14, n)
+	assert.Equal(t, "error", buf.String())
+}
@@ -0,0 +1,61 @@
+// Package backoff calculates delays between retries with exponential growth.
+package backoff
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// DefaultInitialDelay contains the default delay before the first retry.
+const DefaultInitialDelay = time.Second
+
+// DefaultFactor contains the default delay growth rate.
+const DefaultFactor = 2.0
+
+// DefaultMaxDelay contains the default upper bound of the delay.
+const DefaultMaxDelay = 60 * time.Second
+
+// Backoff calculates delay before next attempt. The delay at attempt n is
+// min(Initial * Factor^n, Max). If Jitter is enabled the delay is randomized
+// between half and full calculated value to spread the load.
+type Backoff struct {
+	Initial time.Duration
+	Factor  float64
+	Max     time.Duration
+	Jitter  bool
+
+	attempt int
+}
+
+// New creates a new Backoff.
+func New(initial time.Duration, factor float64, maxDelay time.Duration, jitter bool) *Backoff {
+	return &Backoff{
+		Initial: initial,
+		Factor:  factor,
+		Max:     maxDelay,
+		Jitter:  jitter,
+	}
+}
+
+// Next returns delay before the next attempt and increments attempts counter.
+func (b *Backoff) Next() time.Duration {
+	delay := float64(b.Initial) * math.Pow(b.Factor, float64(b.attempt))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+
+	b.attempt++
+
+	if b.Jitter && delay > 0 {
+		//nolint:gosec // Cryptographically secure random is not needed for jitter.
+		delay = delay/2 + rand.Float64()*delay/2
+	}
+
+	return time.Duration(delay)
+}
+
+// Reset resets attempts counter.
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
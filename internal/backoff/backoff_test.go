@@ -0,0 +1,52 @@
+package backoff_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff_Next(t *testing.T) {
+	t.Run("exponential growth", func(t *testing.T) {
+		b := backoff.New(time.Second, 2, time.Minute, false)
+		assert.Equal(t, 1*time.Second, b.Next())
+		assert.Equal(t, 2*time.Second, b.Next())
+		assert.Equal(t, 4*time.Second, b.Next())
+		assert.Equal(t, 8*time.Second, b.Next())
+	})
+
+	t.Run("custom factor", func(t *testing.T) {
+		b := backoff.New(time.Second, 1.5, time.Minute, false)
+		assert.Equal(t, 1*time.Second, b.Next())
+		assert.Equal(t, 1500*time.Millisecond, b.Next())
+		assert.Equal(t, 2250*time.Millisecond, b.Next())
+	})
+
+	t.Run("max delay", func(t *testing.T) {
+		b := backoff.New(time.Second, 10, 30*time.Second, false)
+		assert.Equal(t, 1*time.Second, b.Next())
+		assert.Equal(t, 10*time.Second, b.Next())
+		assert.Equal(t, 30*time.Second, b.Next())
+		assert.Equal(t, 30*time.Second, b.Next())
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		b := backoff.New(time.Second, 2, time.Minute, true)
+		for i := 0; i < 5; i++ {
+			want := time.Second << i
+			delay := b.Next()
+			assert.GreaterOrEqual(t, delay, want/2)
+			assert.LessOrEqual(t, delay, want)
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		b := backoff.New(time.Second, 2, time.Minute, false)
+		b.Next()
+		b.Next()
+		b.Reset()
+		assert.Equal(t, time.Second, b.Next())
+	})
+}
@@ -0,0 +1,83 @@
+// Package charset converts strings between UTF-8 and legacy encodings which
+// are used by some game servers.
+package charset
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// ErrUnknownEncoding is returned when encoding name is not supported.
+var ErrUnknownEncoding = errors.New("unknown encoding")
+
+// Lookup returns encoding by its name. IANA names (`latin1`, `iso-8859-1`)
+// and WHATWG labels (`cp1252`, `windows-1251`) are supported. Returns nil
+// encoding for empty name and UTF-8.
+func Lookup(name string) (encoding.Encoding, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil, nil //nolint:nilnil // UTF-8 does not need conversion.
+	}
+
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil || enc == nil {
+		if enc, err = htmlindex.Get(name); err != nil {
+			return nil, fmt.Errorf("%w %s", ErrUnknownEncoding, name)
+		}
+	}
+
+	if enc == unicode.UTF8 {
+		return nil, nil //nolint:nilnil // UTF-8 does not need conversion.
+	}
+
+	return enc, nil
+}
+
+// Decode converts s from encoding with name to UTF-8.
+func Decode(name string, s string) (string, error) {
+	enc, err := Lookup(name)
+	if err != nil || enc == nil {
+		return s, err
+	}
+
+	result, err := enc.NewDecoder().String(s)
+	if err != nil {
+		return s, fmt.Errorf("decode %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// Encode converts UTF-8 s to encoding with name.
+func Encode(name string, s string) (string, error) {
+	enc, err := Lookup(name)
+	if err != nil || enc == nil {
+		return s, err
+	}
+
+	result, err := enc.NewEncoder().String(s)
+	if err != nil {
+		return s, fmt.Errorf("encode %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// NewReader returns reader which converts r from encoding with name to UTF-8.
+// r is returned as is for UTF-8.
+func NewReader(name string, r io.Reader) (io.Reader, error) {
+	enc, err := Lookup(name)
+	if err != nil || enc == nil {
+		return r, err
+	}
+
+	return transform.NewReader(r, enc.NewDecoder()), nil
+}
@@ -0,0 +1,71 @@
+package charset_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/charset"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	t.Run("utf-8", func(t *testing.T) {
+		for _, name := range []string{"", "utf-8", "UTF8"} {
+			enc, err := charset.Lookup(name)
+			assert.NoError(t, err)
+			assert.Nil(t, enc)
+		}
+	})
+
+	t.Run("known encodings", func(t *testing.T) {
+		for _, name := range []string{"cp1252", "latin1", "windows-1251", "ISO-8859-1"} {
+			enc, err := charset.Lookup(name)
+			assert.NoError(t, err)
+			assert.NotNil(t, enc)
+		}
+	})
+
+	t.Run("unknown encoding", func(t *testing.T) {
+		_, err := charset.Lookup("pigeon")
+		assert.EqualError(t, err, "unknown encoding pigeon")
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("cp1252", func(t *testing.T) {
+		result, err := charset.Decode("cp1252", "caf\xe9 \x80")
+		assert.NoError(t, err)
+		assert.Equal(t, "café €", result)
+	})
+
+	t.Run("latin1", func(t *testing.T) {
+		result, err := charset.Decode("latin1", "caf\xe9")
+		assert.NoError(t, err)
+		assert.Equal(t, "café", result)
+	})
+
+	t.Run("utf-8", func(t *testing.T) {
+		result, err := charset.Decode("", "café")
+		assert.NoError(t, err)
+		assert.Equal(t, "café", result)
+	})
+}
+
+func TestEncode(t *testing.T) {
+	result, err := charset.Encode("cp1252", "café €")
+	assert.NoError(t, err)
+	assert.Equal(t, "caf\xe9 \x80", result)
+}
+
+func TestNewReader(t *testing.T) {
+	r, err := charset.NewReader("cp1252", strings.NewReader("caf\xe9 \x80"))
+	assert.NoError(t, err)
+
+	result, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "café €", string(result))
+
+	_, err = charset.NewReader("pigeon", strings.NewReader(""))
+	assert.ErrorIs(t, err, charset.ErrUnknownEncoding)
+}
@@ -1,13 +1,18 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+	"text/template"
 
+	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,13 +23,28 @@ const DefaultConfigName = "rcon.yaml"
 // as default unless another value is passed.
 const DefaultConfigEnv = "default"
 
+// JSONEnvVariable is the name of the environment variable which contains the
+// entire config as JSON string. It is checked before the config file.
+const JSONEnvVariable = "RCON_CONFIG_JSON"
+
+// Supported config file formats.
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
 var (
 	// ErrConfigValidation is when config validation completed with errors.
 	ErrConfigValidation = errors.New("config validation error")
 
 	// ErrUnsupportedFileExt is returned when config file has an unsupported
-	// extension. Allowed extensions is `.json`, `.yml`, `.yaml`.
+	// extension. Allowed extensions is `.json`, `.yml`, `.yaml`, `.toml`.
 	ErrUnsupportedFileExt = errors.New("unsupported file extension")
+
+	// ErrUnsupportedFormat is returned when config format is not one of
+	// FormatYAML, FormatJSON or FormatTOML.
+	ErrUnsupportedFormat = errors.New("unsupported format")
 )
 
 // Config allows to take a remote server address and password from
@@ -41,10 +61,34 @@ var (
 // ```.
 type Config map[string]Session
 
+// Option allows to change config file parsing.
+type Option func(s *settings)
+
+type settings struct {
+	template bool
+}
+
+// RenderTemplate renders config file as text/template before parsing. Data
+// of the template contains environment variables in Env map, for example
+// {{ .Env.RCON_PASSWORD }}. Undefined variables are errors. The file on disk
+// is not changed.
+func RenderTemplate(enabled bool) Option {
+	return func(s *settings) {
+		s.template = enabled
+	}
+}
+
 // NewConfig finds and parses config file with remote server credentials.
-func NewConfig(name string) (*Config, error) {
+// If JSONEnvVariable environment variable is set, config is parsed from it
+// and the file is not used.
+func NewConfig(name string, options ...Option) (*Config, error) {
 	cfg := new(Config)
-	if err := cfg.ParseFromFile(name); err != nil {
+
+	if js := os.Getenv(JSONEnvVariable); js != "" {
+		if err := json.Unmarshal([]byte(js), cfg); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", JSONEnvVariable, err)
+		}
+	} else if err := cfg.ParseFromFile(name, options...); err != nil {
 		return nil, fmt.Errorf("parse file: %w", err)
 	}
 
@@ -56,10 +100,15 @@ func NewConfig(name string) (*Config, error) {
 }
 
 // ParseFromFile reads a configuration file from disk and loads its contents into
-// the application's config structure. YAML and JSON files are supported.
-func (cfg *Config) ParseFromFile(name string) error {
+// the application's config structure. YAML, JSON and TOML files are supported.
+func (cfg *Config) ParseFromFile(name string, options ...Option) error {
+	var s settings
+	for _, option := range options {
+		option(&s)
+	}
+
 	if name != "" {
-		return cfg.parse(name)
+		return cfg.parse(name, s)
 	}
 
 	home, err := filepath.Abs(filepath.Dir(os.Args[0]))
@@ -68,7 +117,7 @@ func (cfg *Config) ParseFromFile(name string) error {
 	}
 
 	name = home + "/" + DefaultConfigName
-	if err = cfg.parse(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+	if err = cfg.parse(name, s); err != nil && !errors.Is(err, os.ErrNotExist) {
 		return err
 	}
 
@@ -85,29 +134,116 @@ func (cfg *Config) Validate() error {
 
 	for key, ses := range *cfg {
 		switch ses.Type {
-		case "", ProtocolRCON, ProtocolTELNET, ProtocolWebRCON:
+		case "", ProtocolRCON, ProtocolTELNET, ProtocolWebRCON, ProtocolUnixSocket, ProtocolBattlEye:
 		default:
 			return fmt.Errorf("%w: unsupported type in %s environment", ErrConfigValidation, key)
 		}
+
+		// Host rule keeps the port of the address, so its target must be
+		// a host too. Exact address rule replaces the whole address.
+		for address, target := range ses.RewriteRules {
+			if hasPort(address) != hasPort(target) {
+				return fmt.Errorf("%w: rewrite rule %q => %q in %s environment must map host to host "+
+					"or host:port to host:port", ErrConfigValidation, address, target, key)
+			}
+		}
 	}
 
 	return nil
 }
 
-func (cfg *Config) parse(name string) error {
-	file, err := os.ReadFile(name)
-	if err != nil {
-		return fmt.Errorf("read file: %w", err)
-	}
+// hasPort returns true if address is in host:port form.
+func hasPort(address string) bool {
+	_, _, err := net.SplitHostPort(address)
 
+	return err == nil
+}
+
+// FormatFromExt returns config format by file name extension.
+func FormatFromExt(name string) (string, error) {
 	switch ext := path.Ext(name); ext {
 	case ".yml", ".yaml":
-		err = yaml.Unmarshal(file, cfg)
+		return FormatYAML, nil
 	case ".json":
-		err = json.Unmarshal(file, cfg)
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("%w %s", ErrUnsupportedFileExt, ext)
+	}
+}
+
+// Unmarshal parses data in the given format and loads it into config.
+func (cfg *Config) Unmarshal(data []byte, format string) error {
+	switch format {
+	case FormatYAML:
+		return yaml.Unmarshal(data, cfg)
+	case FormatJSON:
+		return json.Unmarshal(data, cfg)
+	case FormatTOML:
+		return toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// Marshal encodes config in the given format.
+func (cfg *Config) Marshal(format string) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(cfg)
+	case FormatJSON:
+		return json.MarshalIndent(cfg, "", "  ")
+	case FormatTOML:
+		var buf bytes.Buffer
+		err := toml.NewEncoder(&buf).Encode(cfg)
+
+		return buf.Bytes(), err
 	default:
-		err = fmt.Errorf("%w %s", ErrUnsupportedFileExt, ext)
+		return nil, fmt.Errorf("%w %s", ErrUnsupportedFormat, format)
+	}
+}
+
+func (cfg *Config) parse(name string, s settings) error {
+	file, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	format, err := FormatFromExt(name)
+	if err != nil {
+		return err
+	}
+
+	if s.template {
+		if file, err = renderTemplate(name, file); err != nil {
+			return err
+		}
+	}
+
+	return cfg.Unmarshal(file, format)
+}
+
+// renderTemplate executes config file data as text/template with
+// environment variables.
+func renderTemplate(name string, data []byte) ([]byte, error) {
+	tmpl, err := template.New(filepath.Base(name)).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("template: %w", err)
+	}
+
+	env := make(map[string]string)
+
+	for _, variable := range os.Environ() {
+		if key, value, ok := strings.Cut(variable, "="); ok {
+			env[key] = value
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Env map[string]string }{Env: env}); err != nil {
+		return nil, fmt.Errorf("template: %w", err)
 	}
 
-	return err
+	return buf.Bytes(), nil
 }
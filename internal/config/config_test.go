@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gorcon/rcon-cli/internal/config"
 	"github.com/stretchr/testify/assert"
@@ -47,6 +48,26 @@ func TestNewConfig(t *testing.T) {
 		assert.Equal(t, &expected, cfg)
 	})
 
+	t.Run("no errors json env variable", func(t *testing.T) {
+		t.Setenv(config.JSONEnvVariable, fmt.Sprintf(ConfigLayoutJSON, "rust", "127.0.0.1:28016", "password", "", "web"))
+
+		expected := config.Config{
+			"rust": config.Session{Address: "127.0.0.1:28016", Password: "password", Type: config.ProtocolWebRCON},
+		}
+
+		cfg, err := config.NewConfig("nonexist.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, cfg)
+	})
+
+	t.Run("json env variable is incorrect", func(t *testing.T) {
+		t.Setenv(config.JSONEnvVariable, "{")
+
+		cfg, err := config.NewConfig("")
+		assert.EqualError(t, err, "parse RCON_CONFIG_JSON: unexpected end of JSON input")
+		assert.Nil(t, cfg)
+	})
+
 	t.Run("file not exists", func(t *testing.T) {
 		cfg, err := config.NewConfig("nonexist.yaml")
 		if !errors.Is(err, os.ErrNotExist) {
@@ -91,6 +112,33 @@ func TestNewConfig(t *testing.T) {
 		assert.Nil(t, cfg)
 	})
 
+	// Test rendering config template with environment variables.
+	t.Run("template", func(t *testing.T) {
+		t.Setenv("RCON_TEST_PASSWORD", "secret")
+
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, "127.0.0.1:16260", `"{{ .Env.RCON_TEST_PASSWORD }}"`, "", "")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		cfg, err := config.NewConfig(configFileName, config.RenderTemplate(true))
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", (*cfg)[config.DefaultConfigEnv].Password)
+
+		cfg, err = config.NewConfig(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "{{ .Env.RCON_TEST_PASSWORD }}", (*cfg)[config.DefaultConfigEnv].Password)
+
+		data, err := os.ReadFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, stringBody, string(data))
+
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, "", `"{{ .Env.RCON_TEST_UNSET }}"`, "", ""))
+
+		_, err = config.NewConfig(configFileName, config.RenderTemplate(true))
+		assert.ErrorContains(t, err, `map has no entry for key "RCON_TEST_UNSET"`)
+	})
+
 	t.Run("validation failed", func(t *testing.T) {
 		configFileName := "rcon-test-local.json"
 		stringBody := fmt.Sprintf(ConfigLayoutJSON, config.DefaultConfigEnv, "", "", DefaultTestLogName, "pigeon post")
@@ -120,6 +168,275 @@ func TestConfig_Validate(t *testing.T) {
 		err := cfg.Validate()
 		assert.EqualError(t, err, "config validation error: config is not set")
 	})
+
+	t.Run("rewrite rules", func(t *testing.T) {
+		for _, test := range []struct {
+			name    string
+			address string
+			target  string
+			valid   bool
+		}{
+			{name: "host", address: "10.0.0.5", target: "192.168.1.5", valid: true},
+			{name: "host:port", address: "10.0.0.5:27015", target: "192.168.1.5:27016", valid: true},
+			{name: "ipv6 host", address: "fd00::5", target: "::1", valid: true},
+			{name: "host to host:port", address: "10.0.0.5", target: "192.168.1.5:27015"},
+			{name: "host:port to host", address: "10.0.0.5:27015", target: "192.168.1.5"},
+		} {
+			cfg := config.Config{"rust": {RewriteRules: map[string]string{test.address: test.target}}}
+
+			err := cfg.Validate()
+			if test.valid {
+				assert.NoError(t, err, test.name)
+			} else {
+				assert.ErrorIs(t, err, config.ErrConfigValidation, test.name)
+			}
+		}
+	})
+}
+
+func TestSession_Mask(t *testing.T) {
+	t.Run("mask enabled", func(t *testing.T) {
+		ses := config.Session{Password: "Secret", MaskPassword: true}
+		assert.Equal(t, "unknown command: ****, secret", ses.Mask("unknown command: Secret, secret"))
+	})
+
+	t.Run("mask disabled", func(t *testing.T) {
+		ses := config.Session{Password: "Secret"}
+		assert.Equal(t, "unknown command: Secret", ses.Mask("unknown command: Secret"))
+	})
+
+	t.Run("empty password", func(t *testing.T) {
+		ses := config.Session{MaskPassword: true}
+		assert.Equal(t, "unknown command", ses.Mask("unknown command"))
+	})
+}
+
+func TestSession_DialAddress(t *testing.T) {
+	rules := map[string]string{"192.168.1.1:27015": "10.0.0.1:27016", "192.168.1.1": "10.0.0.1", "game.lan": "10.0.0.2"}
+
+	for address, expected := range map[string]string{
+		"192.168.1.1:27015": "10.0.0.1:27016",
+		"192.168.1.1:27020": "10.0.0.1:27020",
+		"game.lan:16260":    "10.0.0.2:16260",
+		"192.168.1.2:27015": "192.168.1.2:27015",
+		"192.168.1.1":       "10.0.0.1",
+	} {
+		ses := config.Session{Address: address, RewriteRules: rules}
+		assert.Equal(t, expected, ses.DialAddress(), address)
+	}
+
+	ses := config.Session{Address: "192.168.1.1:27015"}
+	assert.Equal(t, "192.168.1.1:27015", ses.DialAddress())
+}
+
+func TestRenameEnv(t *testing.T) {
+	t.Run("no errors yaml", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "rust", "127.0.0.1:28016", "password", DefaultTestLogName, "web")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		err := config.RenameEnv(configFileName, "rust", "rust-web")
+		assert.NoError(t, err)
+
+		expected := config.Config{
+			"rust-web": config.Session{
+				Address: "127.0.0.1:28016", Password: "password", Log: DefaultTestLogName, Type: config.ProtocolWebRCON,
+			},
+		}
+
+		cfg, err := config.NewConfig(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, cfg)
+	})
+
+	t.Run("no errors json", func(t *testing.T) {
+		configFileName := "rcon-test-local.json"
+		stringBody := fmt.Sprintf(ConfigLayoutJSON, "rust", "127.0.0.1:28016", "password", DefaultTestLogName, "")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		err := config.RenameEnv(configFileName, "rust", "rust-rcon")
+		assert.NoError(t, err)
+
+		expected := config.Config{
+			"rust-rcon": config.Session{Address: "127.0.0.1:28016", Password: "password", Log: DefaultTestLogName},
+		}
+
+		cfg, err := config.NewConfig(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, cfg)
+	})
+
+	t.Run("no errors toml", func(t *testing.T) {
+		configFileName := "rcon-test-local.toml"
+		stringBody := "# servers\n[rust]\naddress = \"127.0.0.1:28016\"\npassword = \"password\"\ntype = \"web\"\n\n" +
+			"[rust.aliases]\nlp = \"playerlist\"\n\n[zomboid]\naddress = \"127.0.0.1:16260\"\n"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		err := config.RenameEnv(configFileName, "rust", "rust-web")
+		assert.NoError(t, err)
+
+		expected := config.Config{
+			"rust-web": config.Session{
+				Address: "127.0.0.1:28016", Password: "password", Type: config.ProtocolWebRCON,
+				Aliases: map[string]string{"lp": "playerlist"},
+			},
+			"zomboid": config.Session{Address: "127.0.0.1:16260"},
+		}
+
+		cfg, err := config.NewConfig(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, cfg)
+
+		err = config.RenameEnv(configFileName, "zomboid", "rust-web")
+		assert.ErrorIs(t, err, config.ErrEnvExists)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		configFileName := "rcon-test-local.ini"
+		createFile(configFileName, "[rust]\n")
+		defer os.Remove(configFileName)
+
+		err := config.RenameEnv(configFileName, "rust", "rust-web")
+		assert.ErrorIs(t, err, config.ErrUnsupportedFileExt)
+	})
+
+	t.Run("default env is protected", func(t *testing.T) {
+		err := config.RenameEnv("rcon-test-local.yaml", config.DefaultConfigEnv, "rust")
+		assert.ErrorIs(t, err, config.ErrEnvProtected)
+	})
+
+	t.Run("env not found", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "rust", "127.0.0.1:28016", "password", DefaultTestLogName, "")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		err := config.RenameEnv(configFileName, "zomboid", "pz")
+		assert.ErrorIs(t, err, config.ErrEnvNotFound)
+	})
+
+	t.Run("env already exists", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "rust", "127.0.0.1:28016", "password", DefaultTestLogName, "") +
+			"\n" + fmt.Sprintf(ConfigLayoutYAML, "zomboid", "127.0.0.1:16260", "password", DefaultTestLogName, "")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		err := config.RenameEnv(configFileName, "rust", "zomboid")
+		assert.ErrorIs(t, err, config.ErrEnvExists)
+	})
+}
+
+func TestConvert(t *testing.T) {
+	t.Run("no errors yaml to toml", func(t *testing.T) {
+		src, dst := "rcon-test-local.yaml", "rcon-test-local.toml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "rust", "127.0.0.1:28016", "password", DefaultTestLogName, "web") +
+			"\n  timeout: 5s\n  addresses: [\"127.0.0.1:28017\"]"
+		createFile(src, stringBody)
+		defer os.Remove(src)
+		defer os.Remove(dst)
+
+		err := config.Convert(src, config.FormatYAML, dst, config.FormatTOML)
+		assert.NoError(t, err)
+
+		expected, err := config.NewConfig(src)
+		assert.NoError(t, err)
+
+		cfg, err := config.NewConfig(dst)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cfg)
+		assert.Equal(t, 5*time.Second, (*cfg)["rust"].Timeout)
+
+		info, err := os.Stat(dst)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	t.Run("no errors formats from extensions", func(t *testing.T) {
+		src, dst := "rcon-test-local.toml", "rcon-test-local.json"
+		createFile(src, "[rust]\naddress = \"127.0.0.1:28016\"\npassword = \"password\"\n")
+		defer os.Remove(src)
+		defer os.Remove(dst)
+
+		err := config.Convert(src, "", dst, "")
+		assert.NoError(t, err)
+
+		expected := config.Config{
+			"rust": config.Session{Address: "127.0.0.1:28016", Password: "password"},
+		}
+
+		cfg, err := config.NewConfig(dst)
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, cfg)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		src := "rcon-test-local.yaml"
+		createFile(src, fmt.Sprintf(ConfigLayoutYAML, "rust", "127.0.0.1:28016", "password", DefaultTestLogName, ""))
+		defer os.Remove(src)
+
+		err := config.Convert(src, config.FormatYAML, "rcon-test-local.ini", "ini")
+		assert.ErrorIs(t, err, config.ErrUnsupportedFormat)
+	})
+
+	t.Run("unsupported file extension", func(t *testing.T) {
+		err := config.Convert("rcon-test-local.ini", "", "rcon-test-local.toml", "")
+		assert.ErrorIs(t, err, config.ErrUnsupportedFileExt)
+	})
+}
+
+func TestParseEnvVariables(t *testing.T) {
+	environ := []string{
+		"RCON_PROD_ADDRESS=127.0.0.1:16260",
+		"RCON_PROD_PASSWORD=secret=1",
+		"RCON_PROD_TIMEOUT=5s",
+		"RCON_RUST_WEB_ADDRESS=127.0.0.1:28016",
+		"RCON_RUST_WEB_TYPE=web",
+		"RCON_RUST_WEB_HISTORY_FILE=rust.history",
+		"RCON_PROD_UNKNOWN=value",
+		"RCON_ADDRESS=127.0.0.1:1",
+		"HOME=/root",
+	}
+
+	cfg, err := config.ParseEnvVariables(environ, "RCON")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Config{
+		"prod":     {Address: "127.0.0.1:16260", Password: "secret=1", Timeout: 5 * time.Second},
+		"rust_web": {Address: "127.0.0.1:28016", Type: "web", HistoryFile: "rust.history"},
+	}, cfg)
+
+	_, err = config.ParseEnvVariables([]string{"RCON_PROD_TIMEOUT=soon"}, "RCON")
+	assert.Error(t, err)
+}
+
+func TestImportEnvs(t *testing.T) {
+	name := "rcon-test-import.yaml"
+	createFile(name, fmt.Sprintf(ConfigLayoutYAML, "prod", "127.0.0.1:16260", "old", DefaultTestLogName, "rcon"))
+	defer os.Remove(name)
+
+	imported := config.Config{
+		"prod":  {Password: "new"},
+		"stage": {Address: "127.0.0.1:16261", Password: "stage"},
+	}
+
+	cfg, err := config.ImportEnvs(name, imported)
+	assert.NoError(t, err)
+
+	expected := config.Config{
+		"prod":  {Address: "127.0.0.1:16260", Password: "new", Log: DefaultTestLogName, Type: "rcon"},
+		"stage": {Address: "127.0.0.1:16261", Password: "stage"},
+	}
+	assert.Equal(t, expected, cfg)
+
+	saved, err := config.NewConfig(name)
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, saved)
+
+	_, err = config.ImportEnvs(name, config.Config{"prod": {Type: "pigeon"}})
+	assert.ErrorIs(t, err, config.ErrConfigValidation)
 }
 
 func createFile(name, stringBody string) error {
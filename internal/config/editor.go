@@ -0,0 +1,377 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrEnvNotFound is returned when environment is not defined in config file.
+	ErrEnvNotFound = errors.New("environment not found")
+
+	// ErrEnvExists is returned when environment is already defined in config file.
+	ErrEnvExists = errors.New("environment already exists")
+
+	// ErrEnvProtected is returned when trying to modify the default environment.
+	ErrEnvProtected = errors.New("environment is protected")
+
+	// ErrConvertMismatch is returned when converted config differs from
+	// the source after parsing it back.
+	ErrConvertMismatch = errors.New("converted config mismatch")
+)
+
+// importFields sets session fields from environment variables by the field
+// suffix of the variable name.
+var importFields = map[string]func(ses *Session, value string) error{
+	"ADDRESS":      func(ses *Session, value string) error { ses.Address = value; return nil },
+	"PASSWORD":     func(ses *Session, value string) error { ses.Password = value; return nil },
+	"LOG":          func(ses *Session, value string) error { ses.Log = value; return nil },
+	"TYPE":         func(ses *Session, value string) error { ses.Type = value; return nil },
+	"HISTORY_FILE": func(ses *Session, value string) error { ses.HistoryFile = value; return nil },
+	"TIMEOUT": func(ses *Session, value string) (err error) {
+		ses.Timeout, err = time.ParseDuration(value)
+
+		return err
+	},
+}
+
+// RenameEnv renames environment in config file preserving all its fields.
+// The default environment can not be renamed.
+func RenameEnv(name string, oldEnv string, newEnv string) error {
+	if oldEnv == DefaultConfigEnv {
+		return fmt.Errorf("%w: %s", ErrEnvProtected, oldEnv)
+	}
+
+	file, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	switch ext := path.Ext(name); ext {
+	case ".yml", ".yaml":
+		file, err = renameYAML(file, oldEnv, newEnv)
+	case ".json":
+		file, err = renameJSON(file, oldEnv, newEnv)
+	case ".toml":
+		file, err = renameTOML(file, oldEnv, newEnv)
+	default:
+		err = fmt.Errorf("%w %s", ErrUnsupportedFileExt, ext)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	const perm = 0o644
+
+	if err = os.WriteFile(name, file, perm); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	return nil
+}
+
+// Convert reads config file src in from format and writes it to dst file
+// in to format. Empty formats are detected by file extensions. The result
+// is parsed back and compared with the source to be sure no fields are lost.
+func Convert(src string, from string, dst string, to string) error {
+	var err error
+
+	if from == "" {
+		if from, err = FormatFromExt(src); err != nil {
+			return err
+		}
+	}
+
+	if to == "" {
+		if to, err = FormatFromExt(dst); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	cfg := make(Config)
+	if err = cfg.Unmarshal(file, from); err != nil {
+		return fmt.Errorf("%s: %w", from, err)
+	}
+
+	out, err := cfg.Marshal(to)
+	if err != nil {
+		return fmt.Errorf("%s: %w", to, err)
+	}
+
+	check := make(Config)
+	if err = check.Unmarshal(out, to); err != nil {
+		return fmt.Errorf("%s: %w", to, err)
+	}
+
+	if !reflect.DeepEqual(cfg, check) {
+		return ErrConvertMismatch
+	}
+
+	// Converted config contains passwords, so it is readable by owner only.
+	const perm = 0o600
+
+	if err = os.WriteFile(dst, out, perm); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	return nil
+}
+
+// PrintEnvs writes sorted list of config environments to w.
+func (cfg *Config) PrintEnvs(w io.Writer) {
+	envs := make([]string, 0, len(*cfg))
+	for env := range *cfg {
+		envs = append(envs, env)
+	}
+
+	sort.Strings(envs)
+
+	for _, env := range envs {
+		ses := (*cfg)[env]
+
+		protocol := ses.Type
+		if protocol == "" {
+			protocol = DefaultProtocol
+		}
+
+		_, _ = fmt.Fprintf(w, "%s: %s (%s)\n", env, ses.Address, protocol)
+	}
+}
+
+// renameYAML renames the top level key in YAML document. Node tree is used to
+// keep comments and fields order.
+func renameYAML(file []byte, oldEnv string, newEnv string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(file, &doc); err != nil {
+		return nil, fmt.Errorf("yaml: %w", err)
+	}
+
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%w: %s", ErrEnvNotFound, oldEnv)
+	}
+
+	root := doc.Content[0]
+
+	var key *yaml.Node
+
+	for i := 0; i < len(root.Content); i += 2 {
+		switch root.Content[i].Value {
+		case newEnv:
+			return nil, fmt.Errorf("%w: %s", ErrEnvExists, newEnv)
+		case oldEnv:
+			key = root.Content[i]
+		}
+	}
+
+	if key == nil {
+		return nil, fmt.Errorf("%w: %s", ErrEnvNotFound, oldEnv)
+	}
+
+	key.Value = newEnv
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("yaml: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renameJSON renames the top level key in JSON document. Environment fields
+// are kept as is.
+func renameJSON(file []byte, oldEnv string, newEnv string) ([]byte, error) {
+	envs := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(file, &envs); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+
+	if _, ok := envs[newEnv]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrEnvExists, newEnv)
+	}
+
+	fields, ok := envs[oldEnv]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrEnvNotFound, oldEnv)
+	}
+
+	delete(envs, oldEnv)
+	envs[newEnv] = fields
+
+	js, err := json.MarshalIndent(envs, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+
+	return append(js, '\n'), nil
+}
+
+// renameTOML renames the top level table in TOML document. Environment fields
+// are kept, but comments are not preserved because the document is encoded
+// from parsed tables.
+func renameTOML(file []byte, oldEnv string, newEnv string) ([]byte, error) {
+	envs := make(map[string]any)
+	if err := toml.Unmarshal(file, &envs); err != nil {
+		return nil, fmt.Errorf("toml: %w", err)
+	}
+
+	if _, ok := envs[newEnv]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrEnvExists, newEnv)
+	}
+
+	fields, ok := envs[oldEnv]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrEnvNotFound, oldEnv)
+	}
+
+	delete(envs, oldEnv)
+	envs[newEnv] = fields
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(envs); err != nil {
+		return nil, fmt.Errorf("toml: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParseEnvVariables parses environments from variables in "KEY=value" form
+// like `<PREFIX>_<ENV>_<FIELD>`. Environment names are converted to lower
+// case. Supported fields are ADDRESS, PASSWORD, LOG, TYPE, TIMEOUT and
+// HISTORY_FILE, variables with other fields are skipped.
+func ParseEnvVariables(environ []string, prefix string) (Config, error) {
+	fields := make([]string, 0, len(importFields))
+	for field := range importFields {
+		fields = append(fields, field)
+	}
+
+	// Longer fields are checked first so that a field which is a suffix of
+	// another one does not take its variables.
+	sort.Slice(fields, func(i, j int) bool { return len(fields[i]) > len(fields[j]) })
+
+	cfg := make(Config)
+
+	for _, variable := range environ {
+		key, value, _ := strings.Cut(variable, "=")
+
+		rest, ok := strings.CutPrefix(key, prefix+"_")
+		if !ok {
+			continue
+		}
+
+		for _, field := range fields {
+			env, ok := strings.CutSuffix(rest, "_"+field)
+			if !ok || env == "" {
+				continue
+			}
+
+			env = strings.ToLower(env)
+
+			ses := cfg[env]
+			if err := importFields[field](&ses, value); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+
+			cfg[env] = ses
+
+			break
+		}
+	}
+
+	return cfg, nil
+}
+
+// ImportEnvs merges imported environments to config file. Fields which are
+// set in imported environments replace existing values, other fields are
+// kept. Config file is created if it does not exist. Comments are not
+// preserved because file is written from parsed config.
+func ImportEnvs(name string, imported Config) (Config, error) {
+	format, err := FormatFromExt(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := make(Config)
+
+	file, err := os.ReadFile(name)
+	switch {
+	case err == nil:
+		if err = cfg.Unmarshal(file, format); err != nil {
+			return nil, fmt.Errorf("%s: %w", format, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	for env, ses := range imported {
+		cfg[env] = mergeSession(cfg[env], ses)
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	out, err := cfg.Marshal(format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", format, err)
+	}
+
+	const perm = 0o644
+
+	if err = os.WriteFile(name, out, perm); err != nil {
+		return nil, fmt.Errorf("write file: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// mergeSession returns dst with fields which can be imported replaced by
+// non-empty fields of src.
+func mergeSession(dst Session, src Session) Session {
+	if src.Address != "" {
+		dst.Address = src.Address
+	}
+
+	if src.Password != "" {
+		dst.Password = src.Password
+	}
+
+	if src.Log != "" {
+		dst.Log = src.Log
+	}
+
+	if src.Type != "" {
+		dst.Type = src.Type
+	}
+
+	if src.HistoryFile != "" {
+		dst.HistoryFile = src.HistoryFile
+	}
+
+	if src.Timeout != 0 {
+		dst.Timeout = src.Timeout
+	}
+
+	return dst
+}
@@ -4,7 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"regexp"
+	"strings"
+	"text/template"
 	"time"
+
+	"github.com/gorcon/rcon-cli/internal/jsonpath"
+	"github.com/gorcon/rcon-cli/internal/proxy"
+	"github.com/gorcon/rcon-cli/internal/rewrite"
 )
 
 // Allowed protocols.
@@ -12,6 +20,12 @@ const (
 	ProtocolRCON    = "rcon"
 	ProtocolTELNET  = "telnet"
 	ProtocolWebRCON = "web"
+	// ProtocolUnixSocket is RCON protocol over Unix domain socket. The address
+	// is a path to the socket file and password is optional.
+	ProtocolUnixSocket = "unix"
+	// ProtocolBattlEye is BattlEye RCON protocol over UDP used by DayZ and
+	// Arma series servers.
+	ProtocolBattlEye = "battleye"
 )
 
 // DefaultProtocol contains the default protocol for connecting to a
@@ -21,17 +35,315 @@ const DefaultProtocol = ProtocolRCON
 // DefaultTimeout contains the default dial and execute timeout.
 const DefaultTimeout = 10 * time.Second
 
+// DefaultMaxAuthRetries contains the default number of authentication
+// retries after failure.
+const DefaultMaxAuthRetries = 1
+
+// DefaultMaxReconnects contains the default number of reconnection attempts
+// in Interactive mode.
+const DefaultMaxReconnects = 5
+
+// DefaultReconnectDelay contains the default delay before reconnection.
+const DefaultReconnectDelay = time.Second
+
+// PasswordMask replaces password in responses and logs.
+const PasswordMask = "****"
+
+// Credentials contains the password and the protocol type of a server.
+// Empty fields are taken from the session.
+type Credentials struct {
+	Password string
+	Type     string
+}
+
 // Session contains details for making a request on a remote server.
 type Session struct {
-	Address  string `json:"address" yaml:"address"`
-	Password string `json:"password" yaml:"password"`
+	Address  string `json:"address" yaml:"address,omitempty" toml:"address,omitempty"`
+	Password string `json:"password" yaml:"password,omitempty" toml:"password,omitempty"`
+	// Addresses contains the list of servers to execute commands in batch
+	// mode. The password and the protocol type are shared.
+	Addresses []string `json:"addresses" yaml:"addresses,omitempty" toml:"addresses,omitempty"`
+	// Credentials contains passwords and protocol types of servers from
+	// Addresses which differ from the shared ones.
+	Credentials map[string]Credentials `json:"-" yaml:"-" toml:"-"`
 	// Log is the name of the file to which requests will be logged.
 	// If not specified, no logging will be performed.
-	Log        string        `json:"log" yaml:"log"`
-	Type       string        `json:"type" yaml:"type"`
-	SkipErrors bool          `json:"skip_errors" yaml:"skip_errors"`
-	Timeout    time.Duration `json:"timeout" yaml:"timeout"`
-	Variables  bool          `json:"-" yaml:"-"`
+	Log string `json:"log" yaml:"log,omitempty" toml:"log,omitempty"`
+	// CompressLog writes log entries gzip compressed to the Log file with
+	// ".gz" extension.
+	CompressLog bool `json:"-" yaml:"-" toml:"-"`
+	// LogIncludeHeaders writes a header line with start time, address and
+	// protocol when the log file is created.
+	LogIncludeHeaders bool `json:"-" yaml:"-" toml:"-"`
+	// LogSync flushes the log file to disk after every entry so that entries
+	// are not lost if the process is killed.
+	LogSync bool `json:"-" yaml:"-" toml:"-"`
+	// LogTimestampFormat is Go time layout, unix or unixmilli of log entries
+	// time in text and JSON records.
+	LogTimestampFormat string `json:"-" yaml:"-" toml:"-"`
+	// LogMaskPatterns are replaced in order with LogMaskReplacement in the
+	// request and response of log entries.
+	LogMaskPatterns    []*regexp.Regexp `json:"-" yaml:"-" toml:"-"`
+	LogMaskReplacement string           `json:"-" yaml:"-" toml:"-"`
+	// WriteLatency adds command round-trip time to log entries.
+	WriteLatency bool `json:"-" yaml:"-" toml:"-"`
+	// LogSyslog sends log entries to the local syslog daemon in addition to
+	// the log file with SyslogFacility and SyslogSeverity.
+	LogSyslog      bool   `json:"-" yaml:"-" toml:"-"`
+	SyslogFacility string `json:"-" yaml:"-" toml:"-"`
+	SyslogSeverity string `json:"-" yaml:"-" toml:"-"`
+	SyslogTag      string `json:"-" yaml:"-" toml:"-"`
+	// LogUDP is the address of log aggregator which receives log entries as
+	// JSON datagrams.
+	LogUDP     string        `json:"-" yaml:"-" toml:"-"`
+	Type       string        `json:"type" yaml:"type,omitempty" toml:"type,omitempty"`
+	SkipErrors bool          `json:"skip_errors" yaml:"skip_errors,omitempty" toml:"skip_errors,omitempty"`
+	Timeout    time.Duration `json:"timeout" yaml:"timeout,omitempty" toml:"timeout,omitempty"`
+	// ConnectTimeout is the timeout to open connection. Timeout is used if
+	// it is not set.
+	ConnectTimeout time.Duration `json:"connect_timeout" yaml:"connect_timeout,omitempty" toml:"connect_timeout,omitempty"`
+	// SocketBufferSize is the size of receive buffer of the connection in
+	// bytes. Zero keeps the system default.
+	SocketBufferSize int `json:"socket_buffer_size" yaml:"socket_buffer_size,omitempty" toml:"socket_buffer_size,omitempty"`
+	// CacheDNS is the duration for which the resolved IP address of the
+	// server is reused on reconnections. Zero disables caching.
+	CacheDNS  time.Duration `json:"cache_dns" yaml:"cache_dns,omitempty" toml:"cache_dns,omitempty"`
+	Variables bool          `json:"-" yaml:"-" toml:"-"`
+	// EnablePipe allows to pass the response of a command to a local shell
+	// command in Interactive mode using `command | shell command` syntax.
+	EnablePipe bool `json:"-" yaml:"-" toml:"-"`
+	// PreCommand and PostCommand are local shell commands which are run
+	// before sending each command and after receiving the response.
+	PreCommand  string `json:"-" yaml:"-" toml:"-"`
+	PostCommand string `json:"-" yaml:"-" toml:"-"`
+	// OnStart and OnEnd are local shell commands which are run before the
+	// first connection and after the session ends.
+	OnStart string `json:"-" yaml:"-" toml:"-"`
+	OnEnd   string `json:"-" yaml:"-" toml:"-"`
+	// OnResponse is the local shell command which receives each response as
+	// stdin. Its stdout is printed instead of the response.
+	OnResponse string `json:"-" yaml:"-" toml:"-"`
+	// Pager is the local shell command which receives each printed response
+	// as stdin when output is a terminal.
+	Pager string `json:"-" yaml:"-" toml:"-"`
+	// TelnetLineMode executes telnet commands line by line with prompt and
+	// history in Interactive mode instead of attaching input to the
+	// connection.
+	TelnetLineMode bool `json:"-" yaml:"-" toml:"-"`
+	// TelnetNegotiationTimeout is the time to wait for telnet option
+	// requests after connection. Zero uses the default.
+	TelnetNegotiationTimeout time.Duration `json:"-" yaml:"-" toml:"-"`
+	// StdinDelimiter separates commands read in Interactive mode instead of
+	// newline. Line editing is disabled if it is set.
+	StdinDelimiter string `json:"-" yaml:"-" toml:"-"`
+	// SilentAuth suppresses banner and protocol prompt in Interactive mode
+	// when credentials are already set.
+	SilentAuth bool `json:"-" yaml:"-" toml:"-"`
+	// NoPrompt suppresses command prompt and banner in Interactive mode.
+	NoPrompt bool `json:"-" yaml:"-" toml:"-"`
+	// PasteMode starts Interactive mode in paste mode, lines are collected
+	// until :end line and sent as one command.
+	PasteMode bool `json:"-" yaml:"-" toml:"-"`
+	// SessionMaxCommands is the number of commands after which Interactive
+	// mode exits. Failed commands are counted too. Zero disables the limit.
+	SessionMaxCommands int `json:"-" yaml:"-" toml:"-"`
+	// SessionMaxDuration is the time after which Interactive mode exits.
+	// Zero disables the limit.
+	SessionMaxDuration time.Duration `json:"-" yaml:"-" toml:"-"`
+	// ExitOnIdle is the time of waiting for the next command after which
+	// Interactive mode exits. Zero disables the timeout.
+	ExitOnIdle time.Duration `json:"-" yaml:"-" toml:"-"`
+	// FailFast exits Interactive mode on any error without reconnection and
+	// NoFailFast prints errors and continues. Interactive mode exits on
+	// errors except network errors if none of them is set.
+	FailFast   bool `json:"-" yaml:"-" toml:"-"`
+	NoFailFast bool `json:"-" yaml:"-" toml:"-"`
+	// ErrorPattern marks responses which match it as errors.
+	ErrorPattern *regexp.Regexp `json:"-" yaml:"-" toml:"-"`
+	// AbortPattern terminates the session when response matches it, even if
+	// errors are skipped.
+	AbortPattern *regexp.Regexp `json:"-" yaml:"-" toml:"-"`
+	// HistorySearch enables line editing and reverse history search by
+	// Ctrl-R in Interactive mode.
+	HistorySearch bool `json:"-" yaml:"-" toml:"-"`
+	// HistoryFile is the path to the commands history file in Interactive
+	// mode. HistorySize limits the number of saved commands and NoHistory
+	// disables saving.
+	HistoryFile string `json:"history_file" yaml:"history_file,omitempty" toml:"history_file,omitempty"`
+	HistorySize int    `json:"-" yaml:"-" toml:"-"`
+	NoHistory   bool   `json:"-" yaml:"-" toml:"-"`
+	// HTTPProxy tunnels TCP connections to the remote server through HTTP
+	// CONNECT proxy.
+	HTTPProxy *proxy.HTTPDialer `json:"-" yaml:"-" toml:"-"`
+	// NewlineSeparator terminates each response instead of newline and
+	// the commands separator line.
+	NewlineSeparator string `json:"-" yaml:"-" toml:"-"`
+	// ResponsePrefix is prepended to every line of the response.
+	// ResponseTrimPrefix is removed from the first line of the response.
+	ResponsePrefix     string `json:"-" yaml:"-" toml:"-"`
+	ResponseTrimPrefix string `json:"-" yaml:"-" toml:"-"`
+	// ResponseDedup replaces response identical to the previous one with
+	// the unchanged mark. DedupOnChangeExec is the local shell command run
+	// when the response changes.
+	ResponseDedup     bool   `json:"-" yaml:"-" toml:"-"`
+	DedupOnChangeExec string `json:"-" yaml:"-" toml:"-"`
+	// SessionFile is the path to the file with terminal mode state restored
+	// on start and saved on exit.
+	SessionFile string `json:"-" yaml:"-" toml:"-"`
+	// Parallel enables concurrent execution on servers from Addresses.
+	// MaxConcurrent limits the number of servers processed at once, zero
+	// means unlimited.
+	Parallel      bool `json:"-" yaml:"-" toml:"-"`
+	MaxConcurrent int  `json:"-" yaml:"-" toml:"-"`
+	// Watch is the interval of repeated commands execution. WatchCount
+	// limits the number of iterations and WatchClear clears the terminal
+	// before each of them.
+	Watch      time.Duration `json:"-" yaml:"-" toml:"-"`
+	WatchCount int           `json:"-" yaml:"-" toml:"-"`
+	WatchClear bool          `json:"-" yaml:"-" toml:"-"`
+	// FirstMatch prints only the output of the first server from Addresses
+	// which executed the commands successfully. FirstMatchTimeout limits
+	// waiting for it.
+	FirstMatch        bool          `json:"-" yaml:"-" toml:"-"`
+	FirstMatchTimeout time.Duration `json:"-" yaml:"-" toml:"-"`
+	// MaxAuthRetries is the number of authentication retries after failure.
+	MaxAuthRetries int `json:"max_auth_retries" yaml:"max_auth_retries,omitempty" toml:"max_auth_retries,omitempty"`
+	// ResponseEncoding is the encoding of server responses. Responses are
+	// converted to UTF-8 before printing and logging.
+	ResponseEncoding string `json:"response_encoding" yaml:"response_encoding,omitempty" toml:"response_encoding,omitempty"`
+	// CommandEncoding is the encoding which commands are converted to from
+	// UTF-8 before sending.
+	CommandEncoding string `json:"command_encoding" yaml:"command_encoding,omitempty" toml:"command_encoding,omitempty"`
+	// CommandNewline is the line terminator appended to each command: lf,
+	// crlf or cr. Empty keeps the protocol default.
+	CommandNewline string `json:"command_newline" yaml:"command_newline,omitempty" toml:"command_newline,omitempty"`
+	// ResponseNewline is the line ending mode of printed responses: auto,
+	// lf or crlf.
+	ResponseNewline string `json:"response_newline" yaml:"response_newline,omitempty" toml:"response_newline,omitempty"`
+	// Trim is the trim mode of responses: none, trailing-newline or
+	// whitespace. Empty trims whitespace.
+	Trim string `json:"trim" yaml:"trim,omitempty" toml:"trim,omitempty"`
+	// ResponseTemplate reformats responses before printing. It receives
+	// .Response, .Command, .Address, .Timestamp and .Duration variables.
+	ResponseTemplate *template.Template `json:"-" yaml:"-" toml:"-"`
+	// ResponseJSONPath extracts values from JSON responses before printing.
+	// Matched values are joined with newlines.
+	ResponseJSONPath *jsonpath.Path `json:"-" yaml:"-" toml:"-"`
+	// ResponseJSONIndent indents JSON responses by ResponseJSONIndent spaces.
+	// ResponseJSONColor highlights JSON responses with ANSI colors.
+	ResponseJSONIndent int  `json:"-" yaml:"-" toml:"-"`
+	ResponseJSONColor  bool `json:"-" yaml:"-" toml:"-"`
+	// CommandRewrite rewrites each command before CommandPrefix is added.
+	CommandRewrite *rewrite.Expr `json:"-" yaml:"-" toml:"-"`
+	// Lines keeps only the last Lines lines of responses and
+	// ResponseLimitLines keeps the first ResponseLimitLines lines.
+	Lines              int `json:"-" yaml:"-" toml:"-"`
+	ResponseLimitLines int `json:"-" yaml:"-" toml:"-"`
+	// ResponseWrap wraps response lines longer than ResponseWrap runes at
+	// word boundaries.
+	ResponseWrap int `json:"-" yaml:"-" toml:"-"`
+	// TableSeparator splits response lines into table columns if it is set.
+	// TableHeader separates the first line as table header.
+	TableSeparator *regexp.Regexp `json:"-" yaml:"-" toml:"-"`
+	TableHeader    bool           `json:"-" yaml:"-" toml:"-"`
+	// DurationKeys are keys of "key: value" response lines whose values in
+	// seconds are reformatted in DurationStyle, clock or human.
+	DurationKeys  []string `json:"-" yaml:"-" toml:"-"`
+	DurationStyle string   `json:"-" yaml:"-" toml:"-"`
+	// MaskPassword replaces password in responses and logs with PasswordMask.
+	MaskPassword bool `json:"mask_password" yaml:"mask_password,omitempty" toml:"mask_password,omitempty"`
+	// IncludeStats enables printing of connection statistics after each
+	// response to StatsOutput destination.
+	IncludeStats bool   `json:"-" yaml:"-" toml:"-"`
+	StatsOutput  string `json:"-" yaml:"-" toml:"-"`
+	// WriteResponseCode enables printing of OK or ERR status line before
+	// each response. The line ends with ResponseCodeDelimiter.
+	WriteResponseCode     bool   `json:"-" yaml:"-" toml:"-"`
+	ResponseCodeDelimiter string `json:"-" yaml:"-" toml:"-"`
+	// Aliases maps short names to full commands in Interactive mode.
+	Aliases map[string]string `json:"aliases" yaml:"aliases,omitempty" toml:"aliases,omitempty"`
+	// RewriteRules maps server addresses to the addresses which are dialed
+	// instead, e.g. when the server is behind NAT or VPN. A key without port
+	// matches the host with any port and is mapped to a host without port,
+	// it is checked by Config.Validate. The original address is logged.
+	RewriteRules map[string]string `json:"rewrite_rules" yaml:"rewrite_rules,omitempty" toml:"rewrite_rules,omitempty"`
+	// Operator is the name of the user who sends commands. It is written to
+	// the log for audit trail.
+	Operator string `json:"operator" yaml:"operator,omitempty" toml:"operator,omitempty"`
+	// CommandPrefix is prepended to every command sent to remote server.
+	CommandPrefix string `json:"command_prefix" yaml:"command_prefix,omitempty" toml:"command_prefix,omitempty"`
+	// Retries is the number of connection retries after network failure.
+	// Delays between retries grow exponentially.
+	Retries       int           `json:"retries" yaml:"retries,omitempty" toml:"retries,omitempty"`
+	BackoffFactor float64       `json:"backoff_factor" yaml:"backoff_factor,omitempty" toml:"backoff_factor,omitempty"`
+	BackoffMax    time.Duration `json:"backoff_max" yaml:"backoff_max,omitempty" toml:"backoff_max,omitempty"`
+	BackoffJitter bool          `json:"backoff_jitter" yaml:"backoff_jitter,omitempty" toml:"backoff_jitter,omitempty"`
+	// BusyPattern marks responses of overloaded server. The command is resent
+	// up to MaxBusyRetries times, the first delay is BusyDelay and next ones
+	// grow like connection retries.
+	BusyPattern    *regexp.Regexp `json:"-" yaml:"-" toml:"-"`
+	MaxBusyRetries int            `json:"-" yaml:"-" toml:"-"`
+	BusyDelay      time.Duration  `json:"-" yaml:"-" toml:"-"`
+	// ConcurrentCommands is the number of connections which execute commands
+	// concurrently in multi-command mode. Responses are printed in commands
+	// order.
+	ConcurrentCommands int `json:"-" yaml:"-" toml:"-"`
+	// MaxReconnects is the number of consecutive reconnection attempts in
+	// Interactive mode after connection drop.
+	MaxReconnects  int           `json:"max_reconnects" yaml:"max_reconnects,omitempty" toml:"max_reconnects,omitempty"`
+	ReconnectDelay time.Duration `json:"reconnect_delay" yaml:"reconnect_delay,omitempty" toml:"reconnect_delay,omitempty"`
+	// Jitter is the maximum random delay before each command in batch and
+	// multi-command modes. It is used to spread the load on servers.
+	Jitter time.Duration `json:"jitter" yaml:"jitter,omitempty" toml:"jitter,omitempty"`
+}
+
+// DialTimeout returns the timeout to open connection to remote server.
+func (s *Session) DialTimeout() time.Duration {
+	if s.ConnectTimeout != 0 {
+		return s.ConnectTimeout
+	}
+
+	return s.Timeout
+}
+
+// DialAddress returns the address to dial with RewriteRules applied. Exact
+// address rules take precedence over host rules.
+func (s *Session) DialAddress() string {
+	if target, ok := s.RewriteRules[s.Address]; ok {
+		return target
+	}
+
+	host, port, err := net.SplitHostPort(s.Address)
+	if err != nil {
+		return s.Address
+	}
+
+	if target, ok := s.RewriteRules[host]; ok {
+		return net.JoinHostPort(target, port)
+	}
+
+	return s.Address
+}
+
+// Mask replaces password in str with PasswordMask if MaskPassword is enabled.
+// The replacement is case-sensitive.
+func (s *Session) Mask(str string) string {
+	if !s.MaskPassword || s.Password == "" {
+		return str
+	}
+
+	return strings.ReplaceAll(str, s.Password, PasswordMask)
+}
+
+// MaskLog replaces matches of LogMaskPatterns in str with LogMaskReplacement.
+// Patterns are applied in order, $1 in the replacement is expanded to the
+// submatch.
+func (s *Session) MaskLog(str string) string {
+	for _, pattern := range s.LogMaskPatterns {
+		str = pattern.ReplaceAllString(str, s.LogMaskReplacement)
+	}
+
+	return str
 }
 
 func (s *Session) Print(w io.Writer) error {
@@ -0,0 +1,143 @@
+package diagnostic
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// BundleNameLayout is the layout of the default support bundle file name.
+const BundleNameLayout = "rcon-cli-diagnostics-20060102-150405.zip"
+
+// Bundle contains information which is written to the support bundle.
+type Bundle struct {
+	Version string
+	// Flags contains CLI flags used in "name=value" format. Passwords must
+	// be masked by the caller.
+	Flags   []string
+	Config  config.Config
+	Timeout time.Duration
+}
+
+// DefaultBundleName returns support bundle file name with the timestamp.
+func DefaultBundleName(now time.Time) string {
+	return now.Format(BundleNameLayout)
+}
+
+// Write creates ZIP archive with system information, flags, config with
+// masked passwords and the reachability of each config environment.
+func (bundle *Bundle) Write(name string) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+
+	files := []struct {
+		name  string
+		write func(w io.Writer) error
+	}{
+		{"system.txt", bundle.writeSystem},
+		{"flags.txt", bundle.writeFlags},
+		{"config.json", bundle.writeConfig},
+		{"reachability.txt", bundle.writeReachability},
+	}
+
+	for _, f := range files {
+		w, err := archive.Create(f.name)
+		if err != nil {
+			return fmt.Errorf("bundle: %w", err)
+		}
+
+		if err := f.write(w); err != nil {
+			return fmt.Errorf("bundle: %s: %w", f.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+
+	return nil
+}
+
+func (bundle *Bundle) writeSystem(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "rcon-cli: %s\nos: %s\narch: %s\ngo: %s\n",
+		bundle.Version, runtime.GOOS, runtime.GOARCH, runtime.Version())
+
+	return err
+}
+
+func (bundle *Bundle) writeFlags(w io.Writer) error {
+	for _, flag := range bundle.Flags {
+		if _, err := fmt.Fprintln(w, flag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (bundle *Bundle) writeConfig(w io.Writer) error {
+	masked := make(config.Config, len(bundle.Config))
+
+	for env, ses := range bundle.Config {
+		if ses.Password != "" {
+			ses.Password = config.PasswordMask
+		}
+
+		masked[env] = ses
+	}
+
+	js, err := json.MarshalIndent(masked, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(js))
+
+	return err
+}
+
+// writeReachability checks connection to each environment address. Only
+// network is checked, authentication is not performed.
+func (bundle *Bundle) writeReachability(w io.Writer) error {
+	envs := make([]string, 0, len(bundle.Config))
+	for env := range bundle.Config {
+		envs = append(envs, env)
+	}
+
+	sort.Strings(envs)
+
+	for _, env := range envs {
+		ses := bundle.Config[env]
+
+		var result Result
+
+		switch {
+		case ses.Address == "":
+			result = Result{Name: "Connect", Status: StatusSkip, Details: "address is not set"}
+		case ses.Type == config.ProtocolUnixSocket:
+			result = ConnectUnix(ses.Address, bundle.Timeout)
+		default:
+			result = ConnectTCP(ses.DialAddress(), bundle.Timeout)
+		}
+
+		_, err := fmt.Fprintf(w, "%s: [%s] %s %s %s\n", env, result.Status, ses.Address,
+			result.Latency.Round(time.Microsecond), result.Details)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,227 @@
+package diagnostic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// DefaultConnectTimeout contains the default TCP connect timeout.
+const DefaultConnectTimeout = 2 * time.Second
+
+// DefaultCommand is the command which is sent to the remote server to check
+// command execution.
+const DefaultCommand = "status"
+
+// NetworkUnix is the network name of Unix domain socket.
+const NetworkUnix = "unix"
+
+// Step statuses.
+const (
+	StatusPass = "PASS"
+	StatusFail = "FAIL"
+	StatusSkip = "SKIP"
+)
+
+// ErrDiagnosticFailed is returned when one of the diagnostic steps failed.
+var ErrDiagnosticFailed = errors.New("diagnostic failed")
+
+// Client is the interface that groups Execute and Close methods.
+type Client interface {
+	Execute(command string) (string, error)
+	Close() error
+}
+
+// DialFunc creates authorized Client connection to the remote server.
+type DialFunc func() (Client, error)
+
+// Result contains the result of a single diagnostic step.
+type Result struct {
+	Name    string
+	Status  string
+	Latency time.Duration
+	Details string
+}
+
+// Report contains the results of all diagnostic steps.
+type Report struct {
+	Address string
+	Results []Result
+}
+
+// Passed returns true if no one of the steps failed.
+func (report *Report) Passed() bool {
+	for _, result := range report.Results {
+		if result.Status == StatusFail {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Print writes the structured diagnostic report to w.
+func (report *Report) Print(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "Diagnostic report for %s\n", report.Address)
+
+	for _, result := range report.Results {
+		_, _ = fmt.Fprintf(w, "[%s] %-20s %10s", result.Status, result.Name, result.Latency.Round(time.Microsecond))
+		if result.Details != "" {
+			_, _ = fmt.Fprintf(w, "  %s", result.Details)
+		}
+
+		_, _ = fmt.Fprintln(w)
+	}
+
+	status := StatusPass
+	if !report.Passed() {
+		status = StatusFail
+	}
+
+	_, _ = fmt.Fprintf(w, "Result: %s\n", status)
+}
+
+// Run performs all diagnostic steps one by one. Steps after the failed one
+// are skipped. DNS resolving is skipped for unix network.
+func Run(network string, address string, timeout time.Duration, dial DialFunc, command string) *Report {
+	report := Report{Address: address}
+
+	steps := []func() Result{
+		func() Result { return ResolveDNS(address) },
+		func() Result { return ConnectTCP(address, timeout) },
+	}
+
+	if network == NetworkUnix {
+		steps = []func() Result{
+			func() Result { return ConnectUnix(address, timeout) },
+		}
+	}
+
+	for _, step := range steps {
+		result := step()
+		report.Results = append(report.Results, result)
+
+		if result.Status == StatusFail {
+			report.Results = append(report.Results,
+				Result{Name: "Authentication", Status: StatusSkip},
+				Result{Name: "Execute command", Status: StatusSkip},
+			)
+
+			return &report
+		}
+	}
+
+	client, result := Authenticate(dial)
+	report.Results = append(report.Results, result)
+
+	if result.Status == StatusFail {
+		report.Results = append(report.Results, Result{Name: "Execute command", Status: StatusSkip})
+
+		return &report
+	}
+
+	defer client.Close()
+
+	report.Results = append(report.Results, Execute(client, command))
+
+	return &report
+}
+
+// ResolveDNS resolves the host of the address.
+func ResolveDNS(address string) Result {
+	result := Result{Name: "DNS resolve"}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fail(result, err)
+	}
+
+	start := time.Now()
+	addrs, err := net.LookupHost(host)
+	result.Latency = time.Since(start)
+
+	if err != nil {
+		return fail(result, err)
+	}
+
+	return pass(result, strings.Join(addrs, ", "))
+}
+
+// ConnectTCP opens and closes TCP connection to the address.
+func ConnectTCP(address string, timeout time.Duration) Result {
+	return connect("tcp", address, timeout, Result{Name: "TCP connect"})
+}
+
+// ConnectUnix opens and closes connection to the Unix domain socket.
+func ConnectUnix(path string, timeout time.Duration) Result {
+	return connect(NetworkUnix, path, timeout, Result{Name: "Unix socket connect"})
+}
+
+func connect(network string, address string, timeout time.Duration, result Result) Result {
+	if timeout <= 0 {
+		timeout = DefaultConnectTimeout
+	}
+
+	start := time.Now()
+	conn, err := net.DialTimeout(network, address, timeout)
+	result.Latency = time.Since(start)
+
+	if err != nil {
+		return fail(result, err)
+	}
+
+	_ = conn.Close()
+
+	return pass(result, "")
+}
+
+// Authenticate sends the auth request to the remote server using dial.
+func Authenticate(dial DialFunc) (Client, Result) {
+	result := Result{Name: "Authentication"}
+
+	start := time.Now()
+	client, err := dial()
+	result.Latency = time.Since(start)
+
+	if err != nil {
+		return nil, fail(result, err)
+	}
+
+	return client, pass(result, "")
+}
+
+// Execute sends the command to the remote server.
+func Execute(client Client, command string) Result {
+	if command == "" {
+		command = DefaultCommand
+	}
+
+	result := Result{Name: "Execute command"}
+
+	start := time.Now()
+	response, err := client.Execute(command)
+	result.Latency = time.Since(start)
+
+	if err != nil {
+		return fail(result, err)
+	}
+
+	return pass(result, fmt.Sprintf("%q returned %d bytes", command, len(response)))
+}
+
+func pass(result Result, details string) Result {
+	result.Status = StatusPass
+	result.Details = details
+
+	return result
+}
+
+func fail(result Result, err error) Result {
+	result.Status = StatusFail
+	result.Details = err.Error()
+
+	return result
+}
@@ -0,0 +1,142 @@
+package diagnostic_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/diagnostic"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func handlersRCON(c *rcontest.Context) {
+	switch c.Request().Body() {
+	case "status":
+		rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "hostname: test").WriteTo(c.Conn())
+	default:
+		rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "unknown command").WriteTo(c.Conn())
+	}
+}
+
+func TestResolveDNS(t *testing.T) {
+	t.Run("no errors", func(t *testing.T) {
+		result := diagnostic.ResolveDNS("127.0.0.1:16260")
+		assert.Equal(t, diagnostic.StatusPass, result.Status)
+		assert.Equal(t, "127.0.0.1", result.Details)
+	})
+
+	t.Run("missing port", func(t *testing.T) {
+		result := diagnostic.ResolveDNS("127.0.0.1")
+		assert.Equal(t, diagnostic.StatusFail, result.Status)
+	})
+}
+
+func TestConnectTCP(t *testing.T) {
+	server := rcontest.NewServer(rcontest.SetSettings(rcontest.Settings{Password: "password"}))
+	defer server.Close()
+
+	t.Run("no errors", func(t *testing.T) {
+		result := diagnostic.ConnectTCP(server.Addr(), diagnostic.DefaultConnectTimeout)
+		assert.Equal(t, diagnostic.StatusPass, result.Status)
+	})
+
+	t.Run("connection refused", func(t *testing.T) {
+		result := diagnostic.ConnectTCP("127.0.0.1:1", diagnostic.DefaultConnectTimeout)
+		assert.Equal(t, diagnostic.StatusFail, result.Status)
+	})
+}
+
+func TestRun(t *testing.T) {
+	server := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer server.Close()
+
+	dial := func(password string) diagnostic.DialFunc {
+		return func() (diagnostic.Client, error) {
+			return rcon.Dial(server.Addr(), password)
+		}
+	}
+
+	t.Run("no errors", func(t *testing.T) {
+		report := diagnostic.Run("tcp", server.Addr(), diagnostic.DefaultConnectTimeout, dial("password"), "")
+		assert.True(t, report.Passed())
+		assert.Len(t, report.Results, 4)
+
+		w := bytes.Buffer{}
+		report.Print(&w)
+		assert.Contains(t, w.String(), "Result: PASS")
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		report := diagnostic.Run("tcp", server.Addr(), diagnostic.DefaultConnectTimeout, dial("wrong"), "")
+		assert.False(t, report.Passed())
+		assert.Equal(t, diagnostic.StatusFail, report.Results[2].Status)
+		assert.Equal(t, diagnostic.StatusSkip, report.Results[3].Status)
+	})
+
+	t.Run("connection refused", func(t *testing.T) {
+		report := diagnostic.Run("tcp", "127.0.0.1:1", diagnostic.DefaultConnectTimeout, dial("password"), "")
+		assert.False(t, report.Passed())
+		assert.Equal(t, diagnostic.StatusFail, report.Results[1].Status)
+		assert.Equal(t, diagnostic.StatusSkip, report.Results[2].Status)
+	})
+}
+
+func TestBundle_Write(t *testing.T) {
+	server := rcontest.NewServer(rcontest.SetSettings(rcontest.Settings{Password: "password"}))
+	defer server.Close()
+
+	name := filepath.Join(t.TempDir(), diagnostic.DefaultBundleName(time.Now()))
+
+	bundle := diagnostic.Bundle{
+		Version: "0.10.3",
+		Flags:   []string{"env=rust", "password=" + config.PasswordMask},
+		Config: config.Config{
+			"rust":    {Address: server.Addr(), Password: "password"},
+			"zomboid": {Address: "127.0.0.1:1", Password: "password"},
+			"default": {},
+		},
+		Timeout: diagnostic.DefaultConnectTimeout,
+	}
+
+	err := bundle.Write(name)
+	assert.NoError(t, err)
+
+	archive, err := zip.OpenReader(name)
+	assert.NoError(t, err)
+
+	defer archive.Close()
+
+	files := make(map[string]string)
+
+	for _, file := range archive.File {
+		r, err := file.Open()
+		assert.NoError(t, err)
+
+		content, err := io.ReadAll(r)
+		assert.NoError(t, err)
+
+		r.Close()
+
+		files[file.Name] = string(content)
+	}
+
+	assert.Contains(t, files["system.txt"], "rcon-cli: 0.10.3\n")
+	assert.Equal(t, "env=rust\npassword=****\n", files["flags.txt"])
+	assert.NotContains(t, files["config.json"], `"password": "password"`)
+	assert.Contains(t, files["config.json"], `"password": "****"`)
+
+	lines := strings.Split(files["reachability.txt"], "\n")
+	assert.True(t, strings.HasPrefix(lines[0], "default: [SKIP]"), lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "rust: [PASS]"), lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "zomboid: [FAIL]"), lines[2])
+}
@@ -0,0 +1,78 @@
+// Package diff compares fields of structures.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Change contains the name of the field and its differing values.
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// String returns formatted change line.
+func (change Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", change.Field, change.Old, change.New)
+}
+
+// Fields compares exported fields of from and to structures of the same type
+// and returns the changes in the fields order. Field names are taken from
+// tag; fields tagged with "-" are skipped.
+func Fields(from any, to any, tag string) ([]Change, error) {
+	fromValue, toValue := reflect.Indirect(reflect.ValueOf(from)), reflect.Indirect(reflect.ValueOf(to))
+	if fromValue.Kind() != reflect.Struct || fromValue.Type() != toValue.Type() {
+		return nil, fmt.Errorf("diff: expected structures of the same type, got %T and %T", from, to)
+	}
+
+	var changes []Change
+
+	for i := 0; i < fromValue.NumField(); i++ {
+		field := fromValue.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if reflect.DeepEqual(fromValue.Field(i).Interface(), toValue.Field(i).Interface()) {
+			continue
+		}
+
+		changes = append(changes, Change{
+			Field: name,
+			Old:   format(fromValue.Field(i).Interface()),
+			New:   format(toValue.Field(i).Interface()),
+		})
+	}
+
+	return changes, nil
+}
+
+// Print prints changes one per line.
+func Print(w io.Writer, changes []Change) {
+	for _, change := range changes {
+		_, _ = fmt.Fprintln(w, change)
+	}
+}
+
+// format returns printable value. Empty strings are quoted to be visible.
+func format(value any) string {
+	str := fmt.Sprint(value)
+	if str == "" {
+		return `""`
+	}
+
+	return str
+}
@@ -0,0 +1,49 @@
+package diff_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/diff"
+	"github.com/stretchr/testify/assert"
+)
+
+type settings struct {
+	Address  string        `yaml:"address,omitempty"`
+	Timeout  time.Duration `yaml:"timeout"`
+	Hosts    []string      `yaml:"hosts"`
+	Internal bool          `yaml:"-"`
+	Name     string
+	hidden   string
+}
+
+func TestFields(t *testing.T) {
+	t.Run("changes", func(t *testing.T) {
+		from := settings{Address: "127.0.0.1:16260", Timeout: time.Second, Hosts: []string{"a"}, Internal: true, hidden: "a"}
+		to := settings{Timeout: time.Second, Hosts: []string{"a", "b"}, Name: "prod", hidden: "b"}
+
+		changes, err := diff.Fields(from, &to, "yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, []diff.Change{
+			{Field: "address", Old: "127.0.0.1:16260", New: `""`},
+			{Field: "hosts", Old: "[a]", New: "[a b]"},
+			{Field: "Name", Old: `""`, New: "prod"},
+		}, changes)
+
+		w := &bytes.Buffer{}
+		diff.Print(w, changes)
+		assert.Equal(t, "address: 127.0.0.1:16260 -> \"\"\nhosts: [a] -> [a b]\nName: \"\" -> prod\n", w.String())
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		changes, err := diff.Fields(settings{Name: "prod"}, settings{Name: "prod"}, "yaml")
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("different types", func(t *testing.T) {
+		_, err := diff.Fields(settings{}, "settings", "yaml")
+		assert.EqualError(t, err, "diff: expected structures of the same type, got diff_test.settings and string")
+	})
+}
@@ -0,0 +1,71 @@
+// Package dnscache caches resolved host names of server addresses so that
+// repeated connections do not perform DNS lookup every time.
+package dnscache
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// LookupFunc resolves host to the list of IP addresses.
+type LookupFunc func(host string) ([]string, error)
+
+// Resolver resolves host names of addresses and caches the first IP address
+// for the TTL.
+type Resolver struct {
+	ttl    time.Duration
+	lookup LookupFunc
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	ip      string
+	expires time.Time
+}
+
+// NewResolver creates a new Resolver. net.LookupHost is used if lookup is nil.
+func NewResolver(ttl time.Duration, lookup LookupFunc) *Resolver {
+	if lookup == nil {
+		lookup = net.LookupHost
+	}
+
+	return &Resolver{ttl: ttl, lookup: lookup, entries: make(map[string]entry)}
+}
+
+// Resolve replaces host of the "host:port" address with the cached IP
+// address. Host is resolved again when the TTL expires. Addresses with IP
+// hosts are returned as is.
+func (r *Resolver) Resolve(address string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address, fmt.Errorf("resolve: %w", err)
+	}
+
+	if net.ParseIP(host) != nil {
+		return address, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cached, ok := r.entries[host]
+	if !ok || time.Now().After(cached.expires) {
+		ips, err := r.lookup(host)
+		if err != nil {
+			return address, fmt.Errorf("resolve: %w", err)
+		}
+
+		if len(ips) == 0 {
+			return address, fmt.Errorf("resolve: no addresses for %s", host)
+		}
+
+		cached = entry{ip: ips[0], expires: time.Now().Add(r.ttl)}
+		r.entries[host] = cached
+	}
+
+	return net.JoinHostPort(cached.ip, port), nil
+}
@@ -0,0 +1,76 @@
+package dnscache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/dnscache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	lookups := 0
+	lookup := func(host string) ([]string, error) {
+		lookups++
+
+		if host == "unknown.example" {
+			return nil, errors.New("no such host")
+		}
+
+		return []string{"10.0.0.1", "10.0.0.2"}, nil
+	}
+
+	// Test cached address is reused within TTL.
+	t.Run("cached", func(t *testing.T) {
+		lookups = 0
+		resolver := dnscache.NewResolver(time.Minute, lookup)
+
+		for i := 0; i < 3; i++ {
+			address, err := resolver.Resolve("game.example:16260")
+			assert.NoError(t, err)
+			assert.Equal(t, "10.0.0.1:16260", address)
+		}
+
+		assert.Equal(t, 1, lookups)
+	})
+
+	// Test host is resolved again after TTL is expired.
+	t.Run("expired", func(t *testing.T) {
+		lookups = 0
+		resolver := dnscache.NewResolver(time.Millisecond, lookup)
+
+		_, err := resolver.Resolve("game.example:16260")
+		assert.NoError(t, err)
+
+		time.Sleep(5 * time.Millisecond)
+
+		_, err = resolver.Resolve("game.example:16260")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, lookups)
+	})
+
+	t.Run("ip address", func(t *testing.T) {
+		lookups = 0
+		resolver := dnscache.NewResolver(time.Minute, lookup)
+
+		address, err := resolver.Resolve("127.0.0.1:16260")
+		assert.NoError(t, err)
+		assert.Equal(t, "127.0.0.1:16260", address)
+		assert.Equal(t, 0, lookups)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		resolver := dnscache.NewResolver(time.Minute, lookup)
+
+		_, err := resolver.Resolve("unknown.example:16260")
+		assert.EqualError(t, err, "resolve: no such host")
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		resolver := dnscache.NewResolver(time.Minute, lookup)
+
+		_, err := resolver.Resolve("game.example")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// CommandAliases is the command for printing config aliases in Interactive
+// mode.
+const CommandAliases = ":aliases"
+
+// expandAlias replaces the first word of the command with the full command
+// if it is an alias. Other words are kept as arguments.
+func expandAlias(ses *config.Session, command string) string {
+	name, args, hasArgs := strings.Cut(command, " ")
+
+	full, ok := ses.Aliases[name]
+	if !ok {
+		return command
+	}
+
+	if hasArgs {
+		return full + " " + args
+	}
+
+	return full
+}
+
+// printAliases writes sorted list of aliases to w.
+func printAliases(w io.Writer, ses *config.Session) {
+	names := make([]string, 0, len(ses.Aliases))
+	for name := range ses.Aliases {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", name, ses.Aliases[name])
+	}
+}
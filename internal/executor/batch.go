@@ -0,0 +1,494 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/charset"
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// Batch command statuses.
+const (
+	StatusOK      = "OK"
+	StatusError   = "ERROR"
+	StatusSkipped = "SKIPPED"
+)
+
+// ErrNoMatch is returned when no server executed commands successfully in
+// first match mode.
+var ErrNoMatch = errors.New("no server responded successfully")
+
+// CommentPrefix is the prefix of lines which are ignored in batch file.
+const CommentPrefix = "#"
+
+// MaxSummaryResponseLen is the maximum length of the response in the results
+// summary table.
+const MaxSummaryResponseLen = 40
+
+// Result contains the result of the command executed on the server in batch mode.
+type Result struct {
+	Address  string
+	Command  string
+	Status   string
+	Response string
+}
+
+// ReadCommandsFile reads commands from file, one command per line. File is
+// converted from encoding to UTF-8, empty encoding means UTF-8. Empty lines
+// and lines starting with CommentPrefix are skipped.
+func ReadCommandsFile(name string, encoding string) ([]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer file.Close()
+
+	r, err := charset.NewReader(encoding, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadCommands(r)
+}
+
+// ReadCommands reads commands from r, one command per line. Empty lines and
+// lines starting with CommentPrefix are skipped.
+func ReadCommands(r io.Reader) ([]string, error) {
+	var commands []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		command := strings.TrimSpace(scanner.Text())
+		if command == "" || strings.HasPrefix(command, CommentPrefix) {
+			continue
+		}
+
+		commands = append(commands, command)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return commands, fmt.Errorf("read: %w", err)
+	}
+
+	return commands, nil
+}
+
+// ReadCommandGroupsFile reads commands from file like ReadCommandsFile and
+// splits them into groups by lines matching delimiter. Delimiter lines are
+// checked before comments, so commented headers can be used as delimiters.
+// Empty groups are skipped.
+func ReadCommandGroupsFile(name string, encoding string, delimiter *regexp.Regexp) ([][]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer file.Close()
+
+	r, err := charset.NewReader(encoding, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups [][]string
+
+	var group []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		command := strings.TrimSpace(scanner.Text())
+
+		if delimiter.MatchString(command) {
+			if len(group) != 0 {
+				groups = append(groups, group)
+			}
+
+			group = nil
+
+			continue
+		}
+
+		if command == "" || strings.HasPrefix(command, CommentPrefix) {
+			continue
+		}
+
+		group = append(group, command)
+	}
+
+	if len(group) != 0 {
+		groups = append(groups, group)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return groups, fmt.Errorf("read: %w", err)
+	}
+
+	return groups, nil
+}
+
+// ReadAddressFile reads servers from file, one server per line. Line
+// contains the address and optionally the password and the protocol type
+// separated with spaces. Empty lines and lines starting with CommentPrefix
+// are skipped.
+func ReadAddressFile(name string) ([]string, map[string]config.Credentials, error) {
+	const maxFields = 3
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("address file: %w", err)
+	}
+	defer file.Close()
+
+	var addresses []string
+
+	credentials := make(map[string]config.Credentials)
+
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, CommentPrefix) {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) > maxFields {
+			return nil, nil, fmt.Errorf("%w: line %d: expected address [password [type]]", ErrInvalidAddressFile, n)
+		}
+
+		addresses = append(addresses, fields[0])
+
+		if len(fields) > 1 {
+			server := config.Credentials{Password: fields[1]}
+			if len(fields) > 2 {
+				server.Type = fields[2]
+			}
+
+			credentials[fields[0]] = server
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("address file: %w", err)
+	}
+
+	return addresses, credentials, nil
+}
+
+// serverSession returns a copy of the session for the server from
+// addresses with its own credentials if they are set.
+func serverSession(ses *config.Session, address string) *config.Session {
+	serverSes := *ses
+	serverSes.Address = address
+
+	if server, ok := ses.Credentials[address]; ok {
+		if server.Password != "" {
+			serverSes.Password = server.Password
+		}
+
+		if server.Type != "" {
+			serverSes.Type = server.Type
+		}
+	}
+
+	return &serverSes
+}
+
+// hasServerPasswords checks whether all servers from addresses have own
+// passwords, so the shared password is not required.
+func hasServerPasswords(ses *config.Session) bool {
+	if ses.Address != "" || len(ses.Addresses) == 0 {
+		return false
+	}
+
+	for _, address := range ses.Addresses {
+		if ses.Credentials[address].Password == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Batch executes commands on each server from addresses. Servers are
+// processed concurrently if parallel is true, at most MaxConcurrent servers
+// at once if it is set. Errors from any server do not abort others. Output
+// of each server is written to w in addresses order.
+func (executor *Executor) Batch(
+	w io.Writer, ses *config.Session, addresses []string, commands []string, parallel bool,
+) []Result {
+	outputs := make([]bytes.Buffer, len(addresses))
+	results := make([][]Result, len(addresses))
+
+	var wg sync.WaitGroup
+
+	var semaphore chan struct{}
+	if ses.MaxConcurrent > 0 {
+		semaphore = make(chan struct{}, ses.MaxConcurrent)
+	}
+
+	for i, address := range addresses {
+		run := func(i int, address string) {
+			serverSes := serverSession(ses, address)
+
+			results[i] = executor.batchServer(&outputs[i], serverSes, commands)
+		}
+
+		if !parallel {
+			run(i, address)
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, address string) {
+			defer wg.Done()
+
+			if semaphore != nil {
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+			}
+
+			run(i, address)
+		}(i, address)
+	}
+
+	wg.Wait()
+
+	all := make([]Result, 0, len(addresses)*len(commands))
+
+	for i, address := range addresses {
+		_, _ = fmt.Fprintf(w, "==> %s <==\n", address)
+		_, _ = outputs[i].WriteTo(w)
+
+		all = append(all, results[i]...)
+	}
+
+	return all
+}
+
+// FirstMatch executes commands on all servers from addresses concurrently
+// and writes to w the output of the first server which executed all
+// commands successfully. Remaining servers are stopped before the next
+// command. All servers are stopped after timeout if it is not zero.
+func (executor *Executor) FirstMatch(
+	w io.Writer, ses *config.Session, addresses []string, commands []string, timeout time.Duration,
+) error {
+	type match struct {
+		output bytes.Buffer
+		err    error
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	matches := make(chan *match, len(addresses))
+
+	for _, address := range addresses {
+		go func(address string) {
+			serverSes := serverSession(ses, address)
+
+			server := NewExecutor(nil, io.Discard, executor.version)
+			defer server.Close()
+
+			m := match{}
+
+			for _, command := range commands {
+				if m.err = ctx.Err(); m.err != nil {
+					break
+				}
+
+				if m.err = server.Execute(&m.output, serverSes, command); m.err != nil {
+					m.err = fmt.Errorf("%s: %w", address, m.err)
+
+					break
+				}
+			}
+
+			matches <- &m
+		}(address)
+	}
+
+	var expired <-chan time.Time
+
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		expired = timer.C
+	}
+
+	var err error
+
+	for range addresses {
+		select {
+		case m := <-matches:
+			if m.err == nil {
+				_, _ = m.output.WriteTo(w)
+
+				return nil
+			}
+
+			err = m.err
+		case <-expired:
+			return fmt.Errorf("%w in %s", ErrNoMatch, timeout)
+		}
+	}
+
+	return fmt.Errorf("%w: %w", ErrNoMatch, err)
+}
+
+// ExecuteConcurrent executes commands on the session server, up to n of
+// them at once. Commands are sent over one RCON connection and responses are
+// matched to them by packet ID. Responses are written to w in commands order,
+// the output is stopped at the first failed command and its error is
+// returned.
+func (executor *Executor) ExecuteConcurrent(w io.Writer, ses *config.Session, commands []string, n int) error {
+	if ses.Type != "" && ses.Type != config.ProtocolRCON {
+		return fmt.Errorf("execute: %w by %s protocol", ErrConcurrentUnsupported, protocolName(ses.Type))
+	}
+
+	shared := NewExecutor(nil, io.Discard, executor.version)
+	shared.multiplexed = true
+
+	defer shared.Close()
+
+	if err := shared.Dial(ses); err != nil {
+		return fmt.Errorf("execute: %w", err)
+	}
+
+	type output struct {
+		response bytes.Buffer
+		err      error
+	}
+
+	outputs := make([]output, len(commands))
+	indexes := make(chan int, len(commands))
+
+	for i := range commands {
+		indexes <- i
+	}
+
+	close(indexes)
+
+	var wg sync.WaitGroup
+
+	for worker := 0; worker < n && worker < len(commands); worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// Workers have their own response state and share the
+			// connection, which is closed by shared.
+			server := NewExecutor(nil, io.Discard, executor.version)
+			server.client = shared.client
+
+			for i := range indexes {
+				sleepJitter(ses.Jitter)
+
+				outputs[i].err = server.Execute(&outputs[i].response, ses, commands[i])
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for i := range outputs {
+		_, _ = outputs[i].response.WriteTo(w)
+
+		if outputs[i].err != nil {
+			return outputs[i].err
+		}
+
+		if i+1 != len(outputs) {
+			writeCommandsSeparator(w, ses)
+		}
+	}
+
+	return nil
+}
+
+// batchServer executes commands on a single server using its own connection.
+// Remaining commands are skipped after error unless SkipErrors is set.
+func (executor *Executor) batchServer(w io.Writer, ses *config.Session, commands []string) []Result {
+	server := NewExecutor(nil, w, executor.version)
+	defer server.Close()
+
+	results := make([]Result, 0, len(commands))
+	failed := false
+
+	for _, command := range commands {
+		result := Result{Address: ses.Address, Command: command}
+
+		if failed {
+			result.Status = StatusSkipped
+			results = append(results, result)
+
+			continue
+		}
+
+		sleepJitter(ses.Jitter)
+
+		var response bytes.Buffer
+
+		err := server.Execute(io.MultiWriter(w, &response), ses, command)
+
+		result.Status = StatusOK
+		result.Response = strings.TrimSpace(response.String())
+
+		if err != nil {
+			_, _ = fmt.Fprintln(w, err)
+
+			result.Status = StatusError
+			result.Response = err.Error()
+			failed = !ses.SkipErrors || errors.Is(err, ErrSessionAborted)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// PrintSummary writes results summary table to w.
+func PrintSummary(w io.Writer, results []Result) {
+	const padding = 2
+
+	table := tabwriter.NewWriter(w, 0, 0, padding, ' ', 0)
+
+	_, _ = fmt.Fprintln(table, "ADDRESS\tCOMMAND\tSTATUS\tRESPONSE")
+
+	for _, result := range results {
+		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\n",
+			result.Address, result.Command, result.Status, truncate(result.Response, MaxSummaryResponseLen))
+	}
+
+	_ = table.Flush()
+}
+
+// truncate returns the first line of s cut to limit runes.
+func truncate(s string, limit int) string {
+	line, _, multiline := strings.Cut(s, "\n")
+
+	runes := []rune(line)
+	if len(runes) > limit {
+		return string(runes[:limit-3]) + "..."
+	}
+
+	if multiline {
+		return line + "..."
+	}
+
+	return line
+}
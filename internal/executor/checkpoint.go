@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// ReadCheckpoint returns the number of commands executed by the previous run
+// from the checkpoint file. Zero is returned if file does not exist.
+func ReadCheckpoint(name string) (int, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("checkpoint: %w", err)
+	}
+
+	done, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || done < 0 {
+		return 0, fmt.Errorf("checkpoint: invalid file %s", name)
+	}
+
+	return done, nil
+}
+
+// WriteCheckpoint saves the number of executed commands to the checkpoint
+// file.
+func WriteCheckpoint(name string, done int) error {
+	const perm = 0o644
+
+	if err := os.WriteFile(name, []byte(strconv.Itoa(done)+"\n"), perm); err != nil {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// executeCheckpoint executes commands skipping the ones which are already
+// executed according to the checkpoint file. Checkpoint is saved after each
+// successful command and removed when all commands are executed.
+func (executor *Executor) executeCheckpoint(w io.Writer, ses *config.Session, commands []string, name string) error {
+	done, err := ReadCheckpoint(name)
+	if err != nil {
+		return err
+	}
+
+	if done > len(commands) {
+		done = len(commands)
+	}
+
+	if done > 0 {
+		_, _ = fmt.Fprintf(w, "checkpoint: skip %d executed commands\n", done)
+	}
+
+	for i := done; i < len(commands); i++ {
+		if i != done {
+			writeCommandsSeparator(w, ses)
+		}
+
+		if len(commands) > 1 {
+			sleepJitter(ses.Jitter)
+		}
+
+		if err := executor.Execute(w, ses, commands[i]); err != nil {
+			return err
+		}
+
+		if err := WriteCheckpoint(name, i+1); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,684 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/diagnostic"
+	"github.com/gorcon/rcon-cli/internal/diff"
+	"github.com/gorcon/rcon-cli/internal/logger"
+	"github.com/gorcon/rcon-cli/internal/path"
+	telnetproto "github.com/gorcon/rcon-cli/internal/proto/telnet"
+	"github.com/gorcon/rcon-cli/internal/script"
+	"github.com/gorcon/rcon-cli/internal/stream"
+	"github.com/urfave/cli/v2"
+)
+
+// DefaultTailCount is the default number of entries printed by log tail
+// subcommand.
+const DefaultTailCount = 10
+
+// DefaultImportPrefix is the default prefix of environment variables which
+// are imported by config env import subcommand.
+const DefaultImportPrefix = "RCON"
+
+// getCommands returns CLI subcommands.
+func (executor *Executor) getCommands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "test",
+			Usage: "Perform a connection diagnostic to remote server",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "env",
+					Aliases: []string{"e"},
+					Usage:   "Config environment with server credentials",
+				},
+				&cli.StringFlag{
+					Name:  "command",
+					Usage: "Command to execute on remote server",
+					Value: diagnostic.DefaultCommand,
+				},
+			},
+			Action: executor.testConnection,
+		},
+		{
+			Name:  "diagnostics",
+			Usage: "Write support bundle with config, flags, system info and reachability of environments",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "out",
+					Usage: "Path to the ZIP archive. Default ./" + diagnostic.BundleNameLayout + " with current time",
+				},
+			},
+			Action: executor.diagnostics,
+		},
+		{
+			Name:  "stream",
+			Usage: "Print console output of remote server continuously",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "stream-filter",
+					Usage: "Print only lines matching the regular expression",
+				},
+				&cli.DurationFlag{
+					Name:  "stream-timeout",
+					Usage: "Disconnect if no lines were received during the timeout",
+				},
+			},
+			Action: executor.stream,
+		},
+		{
+			Name:      "script",
+			Usage:     "Run Tengo script file which executes commands on remote server by rcon.execute",
+			ArgsUsage: "<file>",
+			Flags: []cli.Flag{
+				&cli.DurationFlag{
+					Name:  "timeout",
+					Usage: "Stop the script if it is not finished in time, 0 disables the limit",
+					Value: script.DefaultTimeout,
+				},
+			},
+			Action: executor.script,
+		},
+		{
+			Name:  "telnet",
+			Usage: "Run terminal mode over telnet protocol with prompt and commands history",
+			Flags: []cli.Flag{
+				&cli.DurationFlag{
+					Name:  "negotiation-timeout",
+					Usage: "Time to wait for telnet option requests which server sends after connection",
+					Value: telnetproto.DefaultNegotiationTimeout,
+				},
+			},
+			Action: executor.telnet,
+		},
+		{
+			Name:  "config",
+			Usage: "Manage configuration file",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "env",
+					Usage: "Manage config environments",
+					Subcommands: []*cli.Command{
+						{
+							Name:      "rename",
+							Usage:     "Rename config environment",
+							ArgsUsage: "<old> <new>",
+							Action:    executor.renameEnv,
+						},
+						{
+							Name:  "import",
+							Usage: "Import environments from <PREFIX>_<ENV>_<FIELD> environment variables",
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  "prefix",
+									Usage: "Prefix of environment variables",
+									Value: DefaultImportPrefix,
+								},
+								&cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "Print imported environments with masked passwords instead of writing config file",
+								},
+							},
+							Action: executor.importEnvs,
+						},
+						{
+							Name:      "test",
+							Usage:     "Verify authentication for config environment",
+							ArgsUsage: "<env>",
+							Flags: []cli.Flag{
+								&cli.DurationFlag{
+									Name:  "connect-timeout",
+									Usage: "Set timeout to open connection",
+									Value: config.DefaultTimeout,
+								},
+								&cli.DurationFlag{
+									Name:  "auth-timeout",
+									Usage: "Set timeout to receive authentication response",
+									Value: config.DefaultTimeout,
+								},
+							},
+							Action: executor.testEnv,
+						},
+					},
+				},
+				{
+					Name:      "diff",
+					Usage:     "Print fields which differ between two config environments",
+					ArgsUsage: "<env1> <env2>",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "show-secrets",
+							Usage: "Print passwords instead of masks. Unsafe, passwords are printed as plain text",
+						},
+					},
+					Action: executor.diffEnvs,
+				},
+				{
+					Name:      "export",
+					Usage:     "Print config environment as environment variables script to eval in shell",
+					ArgsUsage: "<env>",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "format",
+							Usage: "Shell syntax: sh, fish or powershell",
+							Value: ExportFormatSh,
+						},
+						&cli.StringFlag{
+							Name:  "prefix",
+							Usage: "Prefix of environment variables",
+							Value: DefaultEnvPrefix,
+						},
+						&cli.BoolFlag{
+							Name:  "redact",
+							Usage: "Do not export password",
+						},
+					},
+					Action: executor.exportEnv,
+				},
+				{
+					Name:  "convert",
+					Usage: "Convert config file between YAML, JSON and TOML formats",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "from",
+							Usage: "Source format: yaml, json, toml. Detected by extension if not set",
+						},
+						&cli.StringFlag{
+							Name:  "to",
+							Usage: "Target format: yaml, json, toml. Detected by extension if not set",
+						},
+						&cli.StringFlag{
+							Name:  "cfg",
+							Usage: "Path to the source config file",
+						},
+						&cli.StringFlag{
+							Name:  "out",
+							Usage: "Path to the target config file",
+						},
+					},
+					Action: executor.convertConfig,
+				},
+			},
+		},
+		{
+			Name:  "log",
+			Usage: "Read log file",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "tail",
+					Usage: "Print the last log entries",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "file",
+							Usage: "Path to the log file. Log of the config environment is used if not set",
+						},
+						&cli.IntFlag{
+							Name:    "count",
+							Aliases: []string{"n"},
+							Usage:   "Number of entries to print",
+							Value:   DefaultTailCount,
+						},
+					},
+					Action: executor.tailLog,
+				},
+			},
+		},
+		{
+			Name:  "profile",
+			Usage: "Manage profiles with config file and environment pairs",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "add",
+					Usage:     "Add profile",
+					ArgsUsage: "<name> <config> [env]",
+					Action:    executor.addProfile,
+				},
+				{
+					Name:      "remove",
+					Usage:     "Remove profile",
+					ArgsUsage: "<name>",
+					Action:    executor.removeProfile,
+				},
+				{
+					Name:   "list",
+					Usage:  "Print profiles",
+					Action: executor.listProfiles,
+				},
+			},
+		},
+	}
+}
+
+// testConnection performs all diagnostic steps and prints the report.
+func (executor *Executor) testConnection(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	dial := func() (diagnostic.Client, error) {
+		if err := executor.dial(ses); err != nil {
+			return nil, err
+		}
+
+		client := executor.client
+		executor.client = nil
+
+		return client, nil
+	}
+
+	network := "tcp"
+	if ses.Type == config.ProtocolUnixSocket {
+		network = diagnostic.NetworkUnix
+	}
+
+	report := diagnostic.Run(network, ses.DialAddress(), diagnostic.DefaultConnectTimeout, dial, c.String("command"))
+	report.Print(executor.w)
+
+	if !report.Passed() {
+		return diagnostic.ErrDiagnosticFailed
+	}
+
+	return nil
+}
+
+// diagnostics writes the support bundle and prints its file name.
+func (executor *Executor) diagnostics(c *cli.Context) error {
+	name, _, err := configSource(c)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.NewConfig(name, config.RenderTemplate(c.Bool("config-template")))
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	out := path.ExpandHome(c.String("out"))
+	if out == "" {
+		out = diagnostic.DefaultBundleName(time.Now())
+	}
+
+	bundle := diagnostic.Bundle{
+		Version: executor.version,
+		Flags:   usedFlags(c),
+		Config:  *cfg,
+		Timeout: diagnostic.DefaultConnectTimeout,
+	}
+
+	if err := bundle.Write(out); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(executor.w, "Diagnostics bundle written to %s\n", out)
+
+	return nil
+}
+
+// usedFlags returns flags set in all contexts in "name=value" format.
+// Password is replaced with config.PasswordMask.
+func usedFlags(c *cli.Context) []string {
+	var flags []string
+
+	for _, ctx := range c.Lineage() {
+		for _, name := range ctx.LocalFlagNames() {
+			value := fmt.Sprint(ctx.Value(name))
+			if name == "password" || name == "p" {
+				value = config.PasswordMask
+			}
+
+			flags = append(flags, name+"="+value)
+		}
+	}
+
+	sort.Strings(flags)
+
+	return flags
+}
+
+// tailLog prints the last entries of the log file.
+func (executor *Executor) tailLog(c *cli.Context) error {
+	name := path.ExpandHome(c.String("file"))
+	if name == "" {
+		ses, err := executor.NewSession(c)
+		if err != nil {
+			return err
+		}
+
+		name = ses.Log
+	}
+
+	if name == "" {
+		return fmt.Errorf("%w: expected --file", ErrInvalidArguments)
+	}
+
+	entries, err := logger.Tail(name, c.Int("count"))
+	if err != nil {
+		return fmt.Errorf("log: %w", err)
+	}
+
+	for _, entry := range entries {
+		_, _ = fmt.Fprintln(executor.w, entry)
+	}
+
+	return nil
+}
+
+// stream prints console output of remote server until the connection is closed.
+func (executor *Executor) stream(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Type != "" && ses.Type != config.ProtocolRCON {
+		return fmt.Errorf("%w: %s", ErrUnsupportedProtocol, ses.Type)
+	}
+
+	options := []stream.Option{
+		stream.SetAuthTimeout(ses.DialTimeout()),
+		stream.SetIdleTimeout(c.Duration("stream-timeout")),
+	}
+
+	if rate := c.Float64("throttle"); rate > 0 {
+		defer executor.throttle(rate)()
+	}
+
+	if expr := c.String("stream-filter"); expr != "" {
+		filter, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("stream filter: %w", err)
+		}
+
+		options = append(options, stream.SetFilter(filter))
+	}
+
+	conn, err := executor.connect(ses, "tcp", ses.DialAddress())
+	if err != nil {
+		return fmt.Errorf("stream: %w", err)
+	}
+
+	// Log entries are followed as they are received, so buffered output is
+	// flushed after each entry.
+	return stream.Stream(conn, ses.Password, newFlushWriter(executor.w), options...)
+}
+
+// script runs the script file given as the only argument.
+func (executor *Executor) script(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("%w: expected <file>", ErrInvalidArguments)
+	}
+
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" && ses.Type != config.ProtocolUnixSocket {
+		return ErrEmptyPassword
+	}
+
+	return executor.RunScript(executor.w, ses, c.Args().First(), c.Duration("timeout"))
+}
+
+// telnet runs Interactive mode over telnet protocol. Unlike --type telnet
+// the commands are read line by line, so prompt, history and completion work
+// as in rcon terminal mode. Connection flags are taken from the root command.
+func (executor *Executor) telnet(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	ses.Type = config.ProtocolTELNET
+	ses.TelnetLineMode = true
+	ses.TelnetNegotiationTimeout = c.Duration("negotiation-timeout")
+
+	return executor.Interactive(executor.r, executor.w, ses)
+}
+
+// renameEnv renames environment in config file and prints the new config
+// environments listing.
+func (executor *Executor) renameEnv(c *cli.Context) error {
+	const argsCount = 2
+
+	if c.NArg() != argsCount || c.Args().Get(1) == "" {
+		return fmt.Errorf("%w: expected <old> <new>", ErrInvalidArguments)
+	}
+
+	name := c.String("config")
+	if err := config.RenameEnv(name, c.Args().Get(0), c.Args().Get(1)); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	cfg, err := config.NewConfig(name, config.RenderTemplate(c.Bool("config-template")))
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	cfg.PrintEnvs(executor.w)
+
+	return nil
+}
+
+// importEnvs imports environments from environment variables to config
+// file and prints config environments.
+func (executor *Executor) importEnvs(c *cli.Context) error {
+	prefix := c.String("prefix")
+
+	imported, err := config.ParseEnvVariables(os.Environ(), prefix)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	if len(imported) == 0 {
+		return fmt.Errorf("config: %w: no variables with %s_ prefix", config.ErrEnvNotFound, prefix)
+	}
+
+	name := c.String("config")
+
+	if c.Bool("dry-run") {
+		format, err := config.FormatFromExt(name)
+		if err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+
+		for env, ses := range imported {
+			if ses.Password != "" {
+				ses.Password = config.PasswordMask
+			}
+
+			imported[env] = ses
+		}
+
+		out, err := imported.Marshal(format)
+		if err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+
+		_, _ = fmt.Fprint(executor.w, string(out))
+
+		return nil
+	}
+
+	cfg, err := config.ImportEnvs(name, imported)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	cfg.PrintEnvs(executor.w)
+
+	return nil
+}
+
+// diffEnvs prints fields which differ between two config environments.
+// Passwords are compared as is but masked unless show-secrets flag is set.
+func (executor *Executor) diffEnvs(c *cli.Context) error {
+	const argsCount = 2
+
+	if c.NArg() != argsCount {
+		return fmt.Errorf("%w: expected <env1> <env2>", ErrInvalidArguments)
+	}
+
+	cfg, err := config.NewConfig(c.String("config"), config.RenderTemplate(c.Bool("config-template")))
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	sessions := make([]config.Session, argsCount)
+
+	for i, env := range c.Args().Slice() {
+		ses, ok := (*cfg)[env]
+		if !ok {
+			return fmt.Errorf("config: %w: %s", config.ErrEnvNotFound, env)
+		}
+
+		sessions[i] = ses
+	}
+
+	if c.Bool("show-secrets") {
+		_, _ = fmt.Fprintln(os.Stderr, "warning: passwords are printed as plain text")
+	}
+
+	changes, err := diff.Fields(sessions[0], sessions[1], "yaml")
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	for i, change := range changes {
+		if change.Field == "password" && !c.Bool("show-secrets") {
+			changes[i].Old = maskSecret(sessions[0].Password)
+			changes[i].New = maskSecret(sessions[1].Password)
+		}
+	}
+
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintf(executor.w, "Environments %s and %s are identical\n", c.Args().Get(0), c.Args().Get(1))
+
+		return nil
+	}
+
+	diff.Print(executor.w, changes)
+
+	return nil
+}
+
+// maskSecret returns PasswordMask for not empty secret.
+func maskSecret(secret string) string {
+	if secret == "" {
+		return `""`
+	}
+
+	return config.PasswordMask
+}
+
+// exportEnv prints config environment as environment variables script.
+func (executor *Executor) exportEnv(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("%w: expected <env>", ErrInvalidArguments)
+	}
+
+	format := c.String("format")
+	if err := ValidateExportFormat(format); err != nil {
+		return err
+	}
+
+	cfg, err := config.NewConfig(c.String("config"), config.RenderTemplate(c.Bool("config-template")))
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	env := c.Args().First()
+
+	ses, ok := (*cfg)[env]
+	if !ok {
+		return fmt.Errorf("config: %w: %s", config.ErrEnvNotFound, env)
+	}
+
+	WriteExports(executor.w, &ses, strings.ToUpper(c.String("prefix")), format, c.Bool("redact"))
+
+	return nil
+}
+
+// convertConfig converts config file to another format and prints the
+// target file name.
+func (executor *Executor) convertConfig(c *cli.Context) error {
+	src := path.ExpandHome(c.String("cfg"))
+	if src == "" {
+		src = c.String("config")
+	}
+
+	dst := path.ExpandHome(c.String("out"))
+	if dst == "" {
+		return fmt.Errorf("%w: expected --out", ErrInvalidArguments)
+	}
+
+	if err := config.Convert(src, c.String("from"), dst, c.String("to")); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(executor.w, "Converted %s to %s\n", src, dst)
+
+	return nil
+}
+
+// testEnv checks credentials of the config environment and prints OK.
+func (executor *Executor) testEnv(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("%w: expected <env>", ErrInvalidArguments)
+	}
+
+	cfg, err := config.NewConfig(c.String("config"), config.RenderTemplate(c.Bool("config-template")))
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	env := c.Args().First()
+
+	ses, ok := (*cfg)[env]
+	if !ok {
+		return fmt.Errorf("config: %w: %s", config.ErrEnvNotFound, env)
+	}
+
+	ses.ConnectTimeout = c.Duration("connect-timeout")
+	ses.Timeout = c.Duration("auth-timeout")
+
+	if err := executor.CheckCredentials(&ses); err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+
+	_, _ = fmt.Fprintln(executor.w, "OK")
+
+	return nil
+}
+
+// lookupString returns the value of the flag from the nearest context in which
+// it is set. It allows to specify global flags after a subcommand name.
+func lookupString(c *cli.Context, name string) string {
+	for _, ctx := range c.Lineage() {
+		if ctx.IsSet(name) {
+			return ctx.String(name)
+		}
+	}
+
+	return c.String(name)
+}
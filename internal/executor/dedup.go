@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// UnchangedResponse is printed instead of the response which is identical to
+// the previous one when response deduplication is enabled.
+const UnchangedResponse = "[unchanged]"
+
+// dedup compares the response hash with the hash of the previous response
+// and returns true if they are equal. DedupOnChangeExec hook is run when the
+// response differs from the previous one.
+func (executor *Executor) dedup(ses *config.Session, response *Response) bool {
+	hash := sha256.Sum256([]byte(response.Response))
+
+	previous := executor.lastResponse
+	executor.lastResponse = &hash
+
+	if previous == nil {
+		return false
+	}
+
+	if *previous == hash {
+		return true
+	}
+
+	if ses.DedupOnChangeExec != "" {
+		err := executor.runHook(ses.DedupOnChangeExec, HookCommandEnv+"="+response.Command, HookResponseEnv+"="+response.Response)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, fmt.Errorf("dedup on change exec: %w", err))
+		}
+	}
+
+	return false
+}
+
+// printUnchanged writes UnchangedResponse with the response timestamp to w.
+func printUnchanged(w io.Writer, response *Response) {
+	_, _ = fmt.Fprintf(w, "%s %s\n", UnchangedResponse, response.Timestamp.Format(time.RFC3339))
+}
@@ -0,0 +1,43 @@
+package executor
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// DefaultEnvPrefix is the default prefix of environment variables with
+// connection details.
+const DefaultEnvPrefix = "RCON"
+
+// applyEnvVariables fills session fields which are not set by flags from
+// <PREFIX>_ADDRESS, <PREFIX>_PASSWORD, <PREFIX>_TYPE and <PREFIX>_LOG
+// environment variables. The prefix is converted to upper case.
+func applyEnvVariables(c *cli.Context, ses *config.Session) {
+	prefix := strings.ToUpper(c.String("env-prefix"))
+	if prefix == "" {
+		return
+	}
+
+	lookup := func(field string) string {
+		return os.Getenv(prefix + "_" + field)
+	}
+
+	if ses.Address == "" {
+		ses.Address = lookup("ADDRESS")
+	}
+
+	if ses.Password == "" {
+		ses.Password = lookup("PASSWORD")
+	}
+
+	if ses.Log == "" {
+		ses.Log = lookup("LOG")
+	}
+
+	if protocol := lookup("TYPE"); protocol != "" && !c.IsSet("type") {
+		ses.Type = protocol
+	}
+}
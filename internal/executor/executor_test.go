@@ -1,20 +1,32 @@
 package executor_test
 
 import (
+	"archive/zip"
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gorcon/rcon"
 	"github.com/gorcon/rcon-cli/internal/config"
 	"github.com/gorcon/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon-cli/internal/proxy"
 	"github.com/gorcon/rcon/rcontest"
 	"github.com/gorcon/telnet"
 	"github.com/gorcon/telnet/telnettest"
@@ -26,12 +38,78 @@ import (
 const ConfigLayoutJSON = `{"%s": {"address": "%s", "password": "%s", "log": "%s", "type": "%s"}}`
 const ConfigLayoutYAML = "%s:\n  address: %s\n  password: %s\n  log: %s\n  type: %s"
 
+// hookReader returns one line per Read call and runs hook before the
+// second line.
+type hookReader struct {
+	lines []string
+	hook  func()
+	reads int
+}
+
+func (r *hookReader) Read(p []byte) (int, error) {
+	if r.reads >= len(r.lines) {
+		return 0, io.EOF
+	}
+
+	if r.reads == 1 {
+		r.hook()
+	}
+
+	n := copy(p, r.lines[r.reads])
+	r.reads++
+
+	return n, nil
+}
+
+// recordWriter records data of each Write call.
+type recordWriter struct {
+	writes []string
+}
+
+func (w *recordWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+
+	return len(p), nil
+}
+
+// hookWriter runs hook after the first Write call.
+type hookWriter struct {
+	bytes.Buffer
+	hook func()
+}
+
+func (w *hookWriter) Write(p []byte) (int, error) {
+	if w.Len() == 0 {
+		defer w.hook()
+	}
+
+	return w.Buffer.Write(p)
+}
+
 func handlersRCON(c *rcontest.Context) {
 	switch c.Request().Body() {
 	case "help":
 		responseBody := "Can I help you?"
 		rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, responseBody).WriteTo(c.Conn())
+	case "players":
+		responseBody := "Players connected (2):\r\n-admin\r\n-player"
+		rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, responseBody).WriteTo(c.Conn())
+	case "sleep":
+		time.Sleep(200 * time.Millisecond)
+		rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "").WriteTo(c.Conn())
 	default:
+		if echo, ok := strings.CutPrefix(c.Request().Body(), "echo "); ok {
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, echo).WriteTo(c.Conn())
+
+			return
+		}
+
+		if quote, ok := strings.CutPrefix(c.Request().Body(), "quote "); ok {
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, strconv.Quote(quote)).WriteTo(c.Conn())
+
+			return
+		}
+
 		rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "unknown command").WriteTo(c.Conn())
 	}
 }
@@ -126,6 +204,93 @@ func handlersWebRCON() http.Handler {
 	return server
 }
 
+// newSourceServer starts mock Source RCON server on raw TCP listener. If
+// emptyResponse is true, the auth result is preceded by empty
+// SERVERDATA_RESPONSE_VALUE packet as Source Engine servers do.
+func newSourceServer(t *testing.T, password string, emptyResponse bool) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveSource(conn, password, emptyResponse)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveSource(conn net.Conn, password string, emptyResponse bool) {
+	defer conn.Close()
+
+	var auth rcon.Packet
+	if _, err := auth.ReadFrom(conn); err != nil {
+		return
+	}
+
+	if emptyResponse {
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, auth.ID, "").WriteTo(conn)
+	}
+
+	id := auth.ID
+	if auth.Body() != password {
+		id = -1
+	}
+
+	_, _ = rcon.NewPacket(rcon.SERVERDATA_AUTH_RESPONSE, id, "").WriteTo(conn)
+
+	for {
+		var request rcon.Packet
+		if _, err := request.ReadFrom(conn); err != nil {
+			return
+		}
+
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "Can I help you?").WriteTo(conn)
+	}
+}
+
+func TestCheckCredentials(t *testing.T) {
+	for _, emptyResponse := range []bool{true, false} {
+		address := newSourceServer(t, "password", emptyResponse)
+		name := fmt.Sprintf("empty response %t", emptyResponse)
+
+		// Test auth result is read after optional empty response packet.
+		t.Run(name, func(t *testing.T) {
+			app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+			defer app.Close()
+
+			ses := &config.Session{Address: address, Password: "password", Type: config.ProtocolRCON}
+			assert.NoError(t, app.CheckCredentials(ses))
+
+			ses.Password = "wrong"
+			assert.ErrorIs(t, app.CheckCredentials(ses), rcon.ErrAuthFailed)
+		})
+
+		// Test commands are executed after the auth flow.
+		t.Run(name+" execute", func(t *testing.T) {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+			defer app.Close()
+
+			err := app.Run([]string{"", "-a=" + address, "-p=password", "help"})
+			assert.NoError(t, err)
+			assert.Equal(t, "Can I help you?\n", w.String())
+		})
+	}
+}
+
 func TestExecute(t *testing.T) {
 	serverRCON := rcontest.NewServer(
 		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
@@ -175,6 +340,23 @@ func TestExecute(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	// Test connection retries with backoff.
+	t.Run("connection retries", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := config.Session{
+			Address: "127.0.0.1:1", Password: "password", Retries: 2, BackoffFactor: 2, BackoffMax: 10 * time.Millisecond,
+		}
+
+		start := time.Now()
+		err := app.Execute(&w, &ses, "help")
+		assert.Error(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
 	// Test empty command.
 	t.Run("empty command", func(t *testing.T) {
 		w := bytes.Buffer{}
@@ -509,6 +691,30 @@ of your current perk levels in a CSV file next to it.
 			fmt.Println(w.String())
 		})
 	}
+
+	// Test wait for reachable and unreachable server.
+	t.Run("wait for server", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.WaitForServer(&w, &config.Session{Address: serverRCON.Addr(), Password: "password"}, time.Second)
+		assert.NoError(t, err)
+		assert.Empty(t, w.String())
+
+		err = app.WaitForServer(&w, &config.Session{Address: serverRCON.Addr(), Password: "fake"}, time.Second)
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		closed := listener.Addr().String()
+		listener.Close()
+
+		err = app.WaitForServer(&w, &config.Session{Address: closed, Password: "password"}, 100*time.Millisecond)
+		assert.ErrorIs(t, err, executor.ErrTimeout)
+		assert.Equal(t, ".\n", w.String())
+	})
 }
 
 func TestInteractive(t *testing.T) {
@@ -544,6 +750,45 @@ func TestInteractive(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	// Test re-prompt password after authentication failure.
+	t.Run("wrong password retry", func(t *testing.T) {
+		var r bytes.Buffer
+		r.WriteString("password" + "\n")
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		err := app.Interactive(&r, &w, &config.Session{Address: serverRCON.Addr(), Password: "fake", Type: config.ProtocolRCON, MaxAuthRetries: 1})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Authentication failed. Enter password: ")
+		assert.Contains(t, w.String(), "Can I help you?")
+	})
+
+	// Test reconnect after connection drop.
+	t.Run("reconnect", func(t *testing.T) {
+		var r bytes.Buffer
+		r.WriteString("sleep" + "\n")
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{
+			Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON,
+			Timeout: 100 * time.Millisecond, MaxReconnects: 1,
+		}
+		err := app.Interactive(&r, &w, &ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "[reconnected]\n> Can I help you?")
+	})
+
 	// Test long command.
 	t.Run("long command", func(t *testing.T) {
 		r := bytes.Buffer{}
@@ -602,6 +847,89 @@ func TestInteractive(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	// Test commands separated by custom delimiter.
+	t.Run("stdin delimiter", func(t *testing.T) {
+		for delimiter, input := range map[string]string{
+			"---": "echo one\n---\necho two\n---\n",
+			`\0`:  "echo one\x00echo two",
+		} {
+			r := bytes.Buffer{}
+			r.WriteString(input)
+
+			w := bytes.Buffer{}
+
+			app := executor.NewExecutor(&r, &w, "")
+
+			args := []string{"", "-a=" + serverRCON.Addr(), "-p=password", "--silent-auth", "--no-prompt", "--stdin-delimiter=" + delimiter}
+			err := app.Run(args)
+			assert.NoError(t, err)
+			assert.Equal(t, "one\ntwo\n", w.String(), delimiter)
+
+			app.Close()
+		}
+	})
+
+	// Test pasted block is sent as one command.
+	t.Run("paste mode", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString(executor.CommandPaste + "\n")
+		r.WriteString("echo one" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+		r.WriteString(executor.CommandPasteEnd + "\n")
+		r.WriteString(executor.CommandNoPaste + "\n")
+		r.WriteString("echo two" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		args := []string{"", "-a=" + serverRCON.Addr(), "-p=password", "--silent-auth", "--no-prompt"}
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "one\n:q\ntwo\n", w.String())
+	})
+
+	// Test multi-line block is sent in base64 to telnet server.
+	t.Run("paste mode telnet", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("help\nstatus" + "\n")
+		r.WriteString(executor.CommandPasteEnd + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverTELNET.Addr(), Password: "password", Type: config.ProtocolTELNET,
+			TelnetLineMode: true, PasteMode: true, NoPrompt: true,
+		}
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "unknown command 'aGVscApzdGF0dXM='")
+	})
+
+	// Test telnet subcommand executes commands line by line.
+	t.Run("telnet subcommand", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		args := []string{"", "-a=" + serverTELNET.Addr(), "-p=password", "telnet", "--negotiation-timeout=50ms"}
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Waiting commands for "+serverTELNET.Addr())
+		assert.Contains(t, w.String(), "Can I help you?")
+	})
+
 	// Test get Interactive commands WEB RCON.
 	t.Run("get commands web", func(t *testing.T) {
 		r := bytes.Buffer{}
@@ -620,113 +948,2155 @@ func TestInteractive(t *testing.T) {
 		err := app.Interactive(&r, &w, &config.Session{})
 		assert.NoError(t, err)
 	})
-}
-
-func TestNewExecutor(t *testing.T) {
-	serverRCON := rcontest.NewServer(
-		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
-		rcontest.SetCommandHandler(handlersRCON),
-	)
-	defer serverRCON.Close()
+	// Test silent mode without banner.
+	t.Run("silent auth", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
 
-	// Test getting address and password from args. Config ang log are not used.
-	t.Run("getting address and password from args", func(t *testing.T) {
-		r := &bytes.Buffer{}
-		w := &bytes.Buffer{}
+		w := bytes.Buffer{}
 
-		app := executor.NewExecutor(r, w, "")
+		app := executor.NewExecutor(&r, &w, "")
 		defer app.Close()
 
-		args := os.Args[0:1]
-		args = append(args, "-a="+serverRCON.Addr())
-		args = append(args, "-p="+"password")
-		args = append(args, "help")
-
-		err := app.Run(args)
+		ses := config.Session{Address: serverRCON.Addr(), Password: "password", SilentAuth: true}
+		err := app.Interactive(&r, &w, &ses)
 		assert.NoError(t, err)
+		assert.Equal(t, "> Can I help you?\n> ", w.String())
 	})
 
-	// Test getting address and password from config. Log is not used.
-	t.Run("getting address and password from args with log", func(t *testing.T) {
-		configFileName := "rcon-test-local.yaml"
-		logFileName := "rcon-test.log"
-		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", logFileName, "")
-		createFile(configFileName, stringBody)
-
-		defer func() {
-			os.Remove(logFileName)
-			os.Remove(configFileName)
-		}()
+	// Test expanding config aliases.
+	t.Run("aliases", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("h" + "\n")
+		r.WriteString(executor.CommandAliases + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
 
-		r := &bytes.Buffer{}
-		w := &bytes.Buffer{}
+		w := bytes.Buffer{}
 
-		app := executor.NewExecutor(r, w, "")
+		app := executor.NewExecutor(&r, &w, "")
 		defer app.Close()
 
-		args := os.Args[0:1]
-		args = append(args, "-c="+configFileName)
-		args = append(args, "help")
-
-		err := app.Run(args)
+		ses := config.Session{
+			Address: serverRCON.Addr(), Password: "password", SilentAuth: true,
+			Aliases: map[string]string{"h": "help", "lp": "players"},
+		}
+		err := app.Interactive(&r, &w, &ses)
 		assert.NoError(t, err)
+		assert.Equal(t, "> Can I help you?\n> h: help\nlp: players\n> ", w.String())
 	})
 
-	// Test empty address and password. Log is not used.
-	t.Run("empty address and password", func(t *testing.T) {
-		configFileName := "rcon-test-local.yaml"
-		logFileName := "rcon-test.log"
-		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, "", "", logFileName, "")
-		createFile(configFileName, stringBody)
+	// Test config environment is reloaded when the config file is modified.
+	t.Run("config watch", func(t *testing.T) {
+		configFileName := filepath.Join(t.TempDir(), "rcon.yaml")
+		layout := "default:\n  address: %s\n  password: password\n  aliases:\n    h: %s\n"
+		createFile(configFileName, fmt.Sprintf(layout, serverRCON.Addr(), "help"))
 
-		defer func() {
-			os.Remove(logFileName)
-			os.Remove(configFileName)
-		}()
+		// Sleep command gives time to receive the write event.
+		r := &hookReader{lines: []string{"h\n", "sleep\n", "h\n", executor.CommandQuit + "\n"}, hook: func() {
+			createFile(configFileName, fmt.Sprintf(layout, serverRCON.Addr(), "echo reloaded"))
+		}}
 
-		r := &bytes.Buffer{}
-		w := &bytes.Buffer{}
+		w := bytes.Buffer{}
 
-		app := executor.NewExecutor(r, w, "")
+		app := executor.NewExecutor(r, &w, "")
 		defer app.Close()
 
-		args := os.Args[0:1]
-		// Hack to use os.Args[0] in go run
-		args[0] = ""
-		args = append(args, "-c="+configFileName)
-		args = append(args, "help")
+		err := app.Run([]string{"", "-c=" + configFileName, "--config-watch", "--no-prompt"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\nreloaded\n", w.String())
+	})
 
-		err := app.Run(args)
-		assert.EqualError(t, err, "cli: address is not set: to set address add -a host:port")
+	// Test connection is reopened when the address is changed in the config.
+	t.Run("config watch address", func(t *testing.T) {
+		serverNew := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "new server").WriteTo(c.Conn())
+			}),
+		)
+		t.Cleanup(serverNew.Close)
+
+		configFileName := filepath.Join(t.TempDir(), "rcon.yaml")
+		layout := "default:\n  address: %s\n  password: password\n"
+		createFile(configFileName, fmt.Sprintf(layout, serverRCON.Addr()))
+
+		// Sleep command gives time to receive the write event.
+		r := &hookReader{lines: []string{"help\n", "sleep\n", "help\n", executor.CommandQuit + "\n"}, hook: func() {
+			createFile(configFileName, fmt.Sprintf(layout, serverNew.Addr()))
+		}}
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(r, &w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName, "--config-watch", "--no-prompt"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\nnew server\n", w.String())
 	})
 
-	// Test empty password. Log is not used.
-	t.Run("empty password", func(t *testing.T) {
-		configFileName := "rcon-test-local.yaml"
-		logFileName := "rcon-test.log"
-		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "", logFileName, "")
-		createFile(configFileName, stringBody)
+	// Test network errors are retried in terminal mode.
+	t.Run("connection retries", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
 
-		defer func() {
-			os.Remove(logFileName)
-			os.Remove(configFileName)
-		}()
+		defer listener.Close()
 
-		r := &bytes.Buffer{}
-		w := &bytes.Buffer{}
+		// The first connection is dropped, the next ones are forwarded to
+		// the server.
+		go func() {
+			for i := 0; ; i++ {
+				c, err := listener.Accept()
+				if err != nil {
+					return
+				}
 
-		app := executor.NewExecutor(r, w, "")
-		defer app.Close()
+				if i == 0 {
+					c.Close()
 
-		args := os.Args[0:1]
+					continue
+				}
+
+				go func() {
+					defer c.Close()
+
+					s, err := net.Dial("tcp", serverRCON.Addr())
+					if err != nil {
+						return
+					}
+
+					go func() {
+						_, _ = io.Copy(s, c)
+						s.Close()
+					}()
+
+					_, _ = io.Copy(c, s)
+				}()
+			}
+		}()
+
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{
+			Address: listener.Addr().String(), Password: "password", Type: config.ProtocolRCON, NoPrompt: true,
+			Retries: 1, BackoffMax: time.Millisecond,
+		}
+		err = app.Interactive(&r, &w, &ses)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+	})
+
+	// Test disabled prompt when stdin is not a terminal.
+	t.Run("no prompt", func(t *testing.T) {
+		r, pw, err := os.Pipe()
+		assert.NoError(t, err)
+
+		defer r.Close()
+
+		_, _ = pw.WriteString("help" + "\n" + executor.CommandQuit + "\n")
+		pw.Close()
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(r, &w, "")
+		defer app.Close()
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+	})
+
+	// Test fail fast modes with error pattern.
+	t.Run("fail fast", func(t *testing.T) {
+		for _, failFast := range []bool{true, false} {
+			r := bytes.Buffer{}
+			r.WriteString("fake" + "\n")
+			r.WriteString("help" + "\n")
+			r.WriteString(executor.CommandQuit + "\n")
+
+			w := bytes.Buffer{}
+
+			app := executor.NewExecutor(&r, &w, "")
+
+			ses := config.Session{
+				Address: serverRCON.Addr(), Password: "password", SilentAuth: true, NoPrompt: true,
+				FailFast: failFast, NoFailFast: !failFast, ErrorPattern: regexp.MustCompile(`^unknown`),
+			}
+			err := app.Interactive(&r, &w, &ses)
+
+			if failFast {
+				assert.ErrorIs(t, err, executor.ErrResponseMatched)
+				assert.Equal(t, "unknown command\n", w.String())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "unknown command\nexecute: response matches error pattern: fake\nCan I help you?\n", w.String())
+			}
+
+			app.Close()
+		}
+	})
+
+	// Test password is masked in error pattern error.
+	t.Run("error pattern mask password", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("fake password" + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{
+			Address: serverRCON.Addr(), Password: "password", SilentAuth: true, NoPrompt: true,
+			FailFast: true, ErrorPattern: regexp.MustCompile(`^unknown`), MaskPassword: true,
+		}
+		err := app.Interactive(&r, &w, &ses)
+		assert.EqualError(t, err, "execute: response matches error pattern: fake "+config.PasswordMask)
+	})
+
+	// Test session limit counts failed commands.
+	t.Run("session max commands", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("fake" + "\n")
+		r.WriteString("help" + "\n")
+		r.WriteString("players" + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{
+			Address: serverRCON.Addr(), Password: "password", SilentAuth: true, NoPrompt: true,
+			NoFailFast: true, ErrorPattern: regexp.MustCompile(`^unknown`), SessionMaxCommands: 2,
+		}
+		err := app.Interactive(&r, &w, &ses)
+		assert.NoError(t, err)
+		assert.Equal(t, "unknown command\nexecute: response matches error pattern: fake\nCan I help you?\n"+
+			"Session limit of 2 commands reached.\n", w.String())
+	})
+
+	// Test session time limit while waiting for the next command.
+	t.Run("session max duration", func(t *testing.T) {
+		r, pw := io.Pipe()
+		defer pw.Close()
+
+		go func() {
+			_, _ = pw.Write([]byte("help" + "\n"))
+		}()
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{
+			Address: serverRCON.Addr(), Password: "password", SilentAuth: true, NoPrompt: true,
+			SessionMaxDuration: 200 * time.Millisecond,
+		}
+		err := app.Interactive(r, &w, &ses)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\nSession time limit of 200ms reached.\n", w.String())
+	})
+
+	// Test idle timeout does not fire while command is executed.
+	t.Run("exit on idle", func(t *testing.T) {
+		r, pw := io.Pipe()
+		defer pw.Close()
+
+		go func() {
+			_, _ = pw.Write([]byte("sleep" + "\n"))
+			_, _ = pw.Write([]byte("help" + "\n"))
+		}()
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{
+			Address: serverRCON.Addr(), Password: "password", SilentAuth: true, NoPrompt: true,
+			ExitOnIdle: 100 * time.Millisecond,
+		}
+		err := app.Interactive(r, &w, &ses)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\nSession timed out after 100ms of inactivity.\n", w.String())
+	})
+
+	// Test abort pattern overrides no fail fast mode.
+	t.Run("abort on output", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("fake" + "\n")
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{
+			Address: serverRCON.Addr(), Password: "password", SilentAuth: true, NoPrompt: true,
+			NoFailFast: true, AbortPattern: regexp.MustCompile(`^unknown`),
+		}
+		err := app.Interactive(&r, &w, &ses)
+		assert.ErrorIs(t, err, executor.ErrSessionAborted)
+		assert.EqualError(t, err, "execute: session aborted: response matches abort pattern: fake")
+		assert.Equal(t, "unknown command\n", w.String())
+	})
+
+	// Test readline with history search.
+	t.Run("history search", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{Address: serverRCON.Addr(), Password: "password", SilentAuth: true, HistorySearch: true}
+		err := app.Interactive(&r, &w, &ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Can I help you?")
+	})
+
+	// Test saving commands history to file.
+	t.Run("history file", func(t *testing.T) {
+		historyFileName := filepath.Join(t.TempDir(), "rcon", "history")
+
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{Address: serverRCON.Addr(), Password: "password", SilentAuth: true, HistoryFile: historyFileName}
+		err := app.Interactive(&r, &w, &ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Can I help you?")
+
+		history, err := os.ReadFile(historyFileName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(history), "help\n")
+	})
+
+	// Test pipe response to local shell command.
+	t.Run("pipe commands rcon", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("help | tr a-z A-Z" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON, EnablePipe: true}
+		err := app.Interactive(&r, &w, &ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "CAN I HELP YOU?")
+	})
+
+	// Test pipe is ignored when it is not enabled.
+	t.Run("pipe disabled", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("help | tr a-z A-Z" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON}
+		err := app.Interactive(&r, &w, &ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "unknown command")
+	})
+
+	// Test session state is restored from the session file and saved on exit.
+	t.Run("session file", func(t *testing.T) {
+		sessionFileName := filepath.Join(t.TempDir(), "session.json")
+		assert.NoError(t, executor.WriteSessionState(sessionFileName, &executor.SessionState{
+			Address: serverRCON.Addr(), HistoryPosition: 3,
+		}))
+
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := config.Session{Password: "password", Type: config.ProtocolRCON, NoPrompt: true, SessionFile: sessionFileName}
+		err := app.Interactive(&r, &w, &ses)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		state, err := executor.ReadSessionState(sessionFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, &executor.SessionState{
+			Version: executor.SessionStateVersion, Address: serverRCON.Addr(), HistoryPosition: 4,
+		}, state)
+
+		assert.NoError(t, os.WriteFile(sessionFileName, []byte(`{"version": 2}`), 0o600))
+
+		_, err = executor.ReadSessionState(sessionFileName)
+		assert.ErrorIs(t, err, executor.ErrSessionFileVersion)
+	})
+
+	// Test config aliases are not restored from the session file saved by
+	// the previous run when they are disabled.
+	t.Run("session file no config aliases", func(t *testing.T) {
+		dir := t.TempDir()
+		sessionFileName := filepath.Join(dir, "session.json")
+		configFileName := filepath.Join(dir, "rcon.yaml")
+		createFile(configFileName, "default:\n  address: "+serverRCON.Addr()+"\n  password: password\n  aliases:\n    h: help\n")
+
+		args := []string{"", "-c=" + configFileName, "--session-file=" + sessionFileName, "--no-prompt"}
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(strings.NewReader("h\n"+executor.CommandQuit+"\n"), &w, "")
+		defer app.Close()
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		w.Reset()
+
+		app = executor.NewExecutor(strings.NewReader("h\n"+executor.CommandQuit+"\n"), &w, "")
+		defer app.Close()
+
+		err = app.Run(append(args, "--no-config-aliases"))
+		assert.NoError(t, err)
+		assert.Equal(t, "unknown command\n", w.String())
+	})
+}
+
+func TestBatch(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	serverRCON2 := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON2.Close()
+
+	// Test read commands from batch file.
+	t.Run("read commands file", func(t *testing.T) {
+		batchFileName := "rcon-test-batch.txt"
+		createFile(batchFileName, "# comment\nhelp\n\n  players  \n")
+		defer os.Remove(batchFileName)
+
+		commands, err := executor.ReadCommandsFile(batchFileName, "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"help", "players"}, commands)
+	})
+
+	// Test read commands from batch file in legacy encoding.
+	t.Run("read commands file with encoding", func(t *testing.T) {
+		batchFileName := "rcon-test-batch-latin1.txt"
+		createFile(batchFileName, "say caf\xe9\n")
+		defer os.Remove(batchFileName)
+
+		commands, err := executor.ReadCommandsFile(batchFileName, "latin1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"say café"}, commands)
+
+		_, err = executor.ReadCommandsFile(batchFileName, "pigeon")
+		assert.EqualError(t, err, "unknown encoding pigeon")
+	})
+
+	// Test split batch file into groups by delimiter lines.
+	t.Run("read command groups file", func(t *testing.T) {
+		batchFileName := "rcon-test-batch-groups.txt"
+		createFile(batchFileName, "# setup\nhelp\n# test\n\n# teardown\nplayers\necho one\n")
+		defer os.Remove(batchFileName)
+
+		groups, err := executor.ReadCommandGroupsFile(batchFileName, "", regexp.MustCompile(`^# (setup|test|teardown)$`))
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{"help"}, {"players", "echo one"}}, groups)
+	})
+
+	// Test batch file groups are executed with delay.
+	t.Run("split batch on", func(t *testing.T) {
+		batchFileName := "rcon-test-batch-split.txt"
+		createFile(batchFileName, "echo one\n# ---\necho two\necho three\n")
+		defer os.Remove(batchFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		start := time.Now()
+		err := app.Run([]string{
+			"", "-a=" + serverRCON.Addr(), "-p=password", "-f=" + batchFileName,
+			"--split-batch-on=^# ---$", "--split-batch-delay=100ms",
+		})
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+		assert.Equal(t, "one\n--------\ntwo\n--------\nthree\n", w.String())
+
+		err = app.Run([]string{
+			"", "-a=" + serverRCON.Addr(), "-p=password", "-f=" + batchFileName,
+			"--split-batch-on=^# ---$", "--checkpoint-file=rcon-test-batch-split.state",
+		})
+		assert.ErrorIs(t, err, executor.ErrFlagsConflict)
+	})
+
+	// Test servers with own passwords from address file.
+	t.Run("address file", func(t *testing.T) {
+		addressFileName := filepath.Join(t.TempDir(), "servers.txt")
+		createFile(addressFileName, "# fleet\n"+serverRCON.Addr()+" password rcon\n\n"+serverRCON2.Addr()+" password\n")
+
+		servers, credentials, err := executor.ReadAddressFile(addressFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{serverRCON.Addr(), serverRCON2.Addr()}, servers)
+		assert.Equal(t, config.Credentials{Password: "password", Type: "rcon"}, credentials[serverRCON.Addr()])
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err = app.Run([]string{"", "--address-file=" + addressFileName, "echo hi"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "==> "+serverRCON2.Addr()+" <==\nhi\n")
+		assert.Equal(t, 2, strings.Count(w.String(), "echo hi  OK"))
+
+		createFile(addressFileName, serverRCON.Addr()+" password rcon extra\n")
+
+		_, _, err = executor.ReadAddressFile(addressFileName)
+		assert.ErrorIs(t, err, executor.ErrInvalidAddressFile)
+	})
+
+	// Test concurrent commands are sent over one connection and printed in
+	// commands order. Barrier commands are answered only when all of them
+	// are received, so they fail with timeout if they are sent one by one.
+	t.Run("concurrent commands", func(t *testing.T) {
+		var logins atomic.Int32
+		var arrived sync.WaitGroup
+
+		arrived.Add(3)
+
+		server := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetAuthHandler(func(c *rcontest.Context) {
+				logins.Add(1)
+				rcontest.AuthHandler(c)
+			}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				if c.Request().Body() != "barrier" {
+					handlersRCON(c)
+
+					return
+				}
+
+				arrived.Done()
+
+				go func(id int32) {
+					arrived.Wait()
+					rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, id, "passed").WriteTo(c.Conn())
+				}(c.Request().ID)
+			}),
+		)
+		defer server.Close()
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{
+			"", "-a=" + server.Addr(), "-p=password", "-T=1s", "--concurrent-commands=4",
+			"barrier", "echo two", "barrier", "barrier",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), logins.Load())
+		assert.Equal(t, "passed\n--------\ntwo\n--------\npassed\n--------\npassed\n", w.String())
+
+		err = app.Run([]string{
+			"", "-a=127.0.0.1:1", "-p=password", "-t=telnet", "--concurrent-commands=2", "help", "help",
+		})
+		assert.ErrorIs(t, err, executor.ErrConcurrentUnsupported)
+	})
+
+	// Test parallel execution with a failed server.
+	t.Run("parallel with failed server", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		addresses := []string{serverRCON.Addr(), "127.0.0.1:1", serverRCON2.Addr()}
+		ses := config.Session{Password: "password", Timeout: time.Second}
+
+		results := app.Batch(&w, &ses, addresses, []string{"help", "unknown"}, true)
+		assert.Len(t, results, 6)
+		assert.Equal(t, executor.Result{
+			Address: serverRCON.Addr(), Command: "help", Status: executor.StatusOK, Response: "Can I help you?",
+		}, results[0])
+		assert.Equal(t, executor.StatusError, results[2].Status)
+		assert.Equal(t, executor.StatusSkipped, results[3].Status)
+		assert.Equal(t, executor.StatusOK, results[4].Status)
+
+		summary := bytes.Buffer{}
+		executor.PrintSummary(&summary, results)
+		assert.Contains(t, summary.String(), "ADDRESS")
+		assert.Contains(t, summary.String(), "Can I help you?")
+	})
+
+	// Test limit of concurrently processed servers.
+	t.Run("max concurrent", func(t *testing.T) {
+		addresses := []string{serverRCON.Addr(), serverRCON.Addr(), serverRCON.Addr()}
+
+		for limit, sequential := range map[int]bool{1: true, 0: false} {
+			w := bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, &w, "")
+
+			ses := config.Session{Password: "password", Timeout: time.Second, MaxConcurrent: limit}
+
+			start := time.Now()
+			results := app.Batch(&w, &ses, addresses, []string{"sleep"}, true)
+			elapsed := time.Since(start)
+
+			assert.Len(t, results, 3)
+			assert.Equal(t, sequential, elapsed >= 600*time.Millisecond, elapsed)
+
+			app.Close()
+		}
+	})
+
+	// Test printing the first successful response of several servers.
+	t.Run("first match", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := config.Session{Password: "password", Timeout: time.Second}
+
+		err := app.FirstMatch(&w, &ses, []string{"127.0.0.1:1", serverRCON.Addr()}, []string{"help"}, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		err = app.FirstMatch(&w, &ses, []string{"127.0.0.1:1", "127.0.0.1:2"}, []string{"help"}, 0)
+		assert.ErrorIs(t, err, executor.ErrNoMatch)
+
+		err = app.FirstMatch(&w, &ses, []string{serverRCON.Addr()}, []string{"sleep"}, 50*time.Millisecond)
+		assert.EqualError(t, err, "no server responded successfully in 50ms")
+	})
+
+	// Test batch file with several addresses from args.
+	t.Run("batch file with addresses", func(t *testing.T) {
+		batchFileName := "rcon-test-batch.txt"
+		createFile(batchFileName, "help\n")
+		defer os.Remove(batchFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-A="+serverRCON.Addr(), "-A="+serverRCON2.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "-f="+batchFileName)
+		args = append(args, "--parallel")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "==> "+serverRCON2.Addr()+" <==")
+	})
+
+	// Test batch file resuming from checkpoint after failure.
+	t.Run("batch file with checkpoint", func(t *testing.T) {
+		batchFileName := "rcon-test-batch-checkpoint.txt"
+		checkpointFileName := "rcon-test-batch-checkpoint.state"
+		defer os.Remove(batchFileName)
+		defer os.Remove(checkpointFileName)
+
+		run := func(args ...string) (string, error) {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+			defer app.Close()
+
+			err := app.Run(append([]string{
+				"", "-a=" + serverRCON.Addr(), "-p=password", "-f=" + batchFileName,
+				"--checkpoint-file=" + checkpointFileName, "--error-pattern=unknown",
+			}, args...))
+
+			return w.String(), err
+		}
+
+		createFile(batchFileName, "echo one\nunknown\necho three\n")
+
+		_, err := run()
+		assert.ErrorIs(t, err, executor.ErrResponseMatched)
+
+		done, err := executor.ReadCheckpoint(checkpointFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, done)
+
+		createFile(batchFileName, "echo one\necho two\necho three\n")
+
+		output, err := run()
+		assert.NoError(t, err)
+		assert.Equal(t, "checkpoint: skip 1 executed commands\ntwo\n--------\nthree\n", output)
+		assert.NoFileExists(t, checkpointFileName)
+
+		assert.NoError(t, executor.WriteCheckpoint(checkpointFileName, 2))
+
+		output, err = run("--reset-checkpoint")
+		assert.NoError(t, err)
+		assert.Equal(t, "one\n--------\ntwo\n--------\nthree\n", output)
+	})
+
+	// Test batch file path relative to the home directory.
+	t.Run("batch file in home directory", func(t *testing.T) {
+		current, err := user.Current()
+		if err != nil {
+			t.Skip(err)
+		}
+
+		batchFileName := filepath.Join(current.HomeDir, "rcon-test-batch-home.txt")
+		createFile(batchFileName, "help\n")
+		defer os.Remove(batchFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-f=~/rcon-test-batch-home.txt"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+	})
+}
+
+func TestNewExecutor(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// Test getting address and password from args. Config ang log are not used.
+	t.Run("getting address and password from args", func(t *testing.T) {
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+	})
+
+	// Test getting address and password from config. Log is not used.
+	t.Run("getting address and password from args with log", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		logFileName := "rcon-test.log"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", logFileName, "")
+		createFile(configFileName, stringBody)
+
+		defer func() {
+			os.Remove(logFileName)
+			os.Remove(configFileName)
+		}()
+
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+	})
+
+	// Test empty address and password. Log is not used.
+	t.Run("empty address and password", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		logFileName := "rcon-test.log"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, "", "", logFileName, "")
+		createFile(configFileName, stringBody)
+
+		defer func() {
+			os.Remove(logFileName)
+			os.Remove(configFileName)
+		}()
+
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		// Hack to use os.Args[0] in go run
+		args[0] = ""
+		args = append(args, "-c="+configFileName)
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.EqualError(t, err, "cli: address is not set: to set address add -a host:port")
+	})
+
+	// Test empty password. Log is not used.
+	t.Run("empty password", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		logFileName := "rcon-test.log"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "", logFileName, "")
+		createFile(configFileName, stringBody)
+
+		defer func() {
+			os.Remove(logFileName)
+			os.Remove(configFileName)
+		}()
+
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
 		// Hack to use os.Args[0] in go run
 		args[0] = ""
 		args = append(args, "-a="+serverRCON.Addr())
-		args = append(args, "-c="+configFileName)
+		args = append(args, "-c="+configFileName)
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.EqualError(t, err, "cli: password is not set: to set password add -p password")
+	})
+
+	// Test connection diagnostic subcommand.
+	t.Run("test subcommand", func(t *testing.T) {
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "test", "--command=help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Result: PASS")
+	})
+
+	// Test command prefix from file.
+	t.Run("command prefix file", func(t *testing.T) {
+		prefixFileName := "rcon-test-prefix.txt"
+		createFile(prefixFileName, "he")
+		defer os.Remove(prefixFileName)
+
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--command-prefix-file="+prefixFileName)
+		args = append(args, "lp")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+	})
+
+	// Test response template.
+	t.Run("response template", func(t *testing.T) {
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--response-template=[{{.Address}}] {{.Command}}: {{.Response}}")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "["+serverRCON.Addr()+"] help: Can I help you?\n", w.String())
+	})
+
+	// Test invalid response template.
+	t.Run("invalid response template", func(t *testing.T) {
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--response-template={{.Response")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.ErrorContains(t, err, "cli: response template: template: response:1: unclosed action")
+	})
+
+	// Test connection statistics.
+	t.Run("include stats", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--include-stats", "--stats-output=stdout")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Regexp(t, `^Can I help you\?\n# stats: connect=\S+ auth=\S+ cmd=\S+ total=\S+\n$`, w.String())
+	})
+
+	// Test passing responses to local program.
+	t.Run("on response", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--on-response=tr a-z A-Z")
+		args = append(args, "help", "players")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "CAN I HELP YOU?\n"+executor.CommandsResponseSeparator+"\nPLAYERS CONNECTED (2):\n-ADMIN\n-PLAYER\n", w.String())
+	})
+
+	// Test response code line before responses.
+	t.Run("write response code", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--write-response-code", "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "OK\nCan I help you?\n", w.String())
+
+		w.Reset()
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--write-response-code",
+			`--response-code-delimiter=\x00`, "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "OK\x00Can I help you?\n", w.String())
+
+		w.Reset()
+
+		app = executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=wrong", "--write-response-code", "help"})
+		assert.EqualError(t, err, "cli: execute: auth: rcon: authentication failed")
+		assert.Equal(t, "ERR: execute: auth: rcon: authentication failed\n", w.String())
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--write-response-code",
+			`--response-code-delimiter=\q`, "help"})
+		assert.EqualError(t, err, "cli: response code delimiter: invalid syntax")
+	})
+
+	// Test pager is disabled when output is not a terminal.
+	t.Run("pager", func(t *testing.T) {
+		t.Setenv(executor.PagerEnv, "tr a-z A-Z")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--pager", "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+	})
+
+	// Test rendering responses as table.
+	t.Run("format table", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--format-table", "--table-sep= ", "--table-header")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can  I  help  you?\n---  -  ----  ----\n", w.String())
+	})
+
+	// Test pretty-printing of JSON responses.
+	t.Run("response json indent", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--response-json-indent=2",
+			"--response-json-color", `echo {"players":["admin"]}`})
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"players\": [\n    \"admin\"\n  ]\n}\n", w.String())
+	})
+
+	// Test formatting durations of response keys.
+	t.Run("format duration", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--format-duration",
+			"--duration-keys=uptime,playTime", "--duration-style=human", "echo uptime: 86400"})
+		assert.NoError(t, err)
+		assert.Equal(t, "uptime: 24h0m0s\n", w.String())
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--format-duration",
+			"--duration-style=days", "help"})
+		assert.ErrorIs(t, err, executor.ErrUnsupportedDurationStyle)
+	})
+
+	// Test socket buffer size is set to rcon connection and is not supported
+	// by battleye protocol.
+	t.Run("socket buffer size", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--socket-buffer-size=65536", "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		app = executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err = app.Run([]string{"", "-a=127.0.0.1:1", "-p=password", "-t=battleye", "--socket-buffer-size=65536", "help"})
+		assert.EqualError(t, err, "cli: execute: auth: socket buffer size is not supported by battleye protocol")
+	})
+
+	// Test cached DNS resolution.
+	t.Run("cache dns", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--cache-dns=1m", "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		app2 := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app2.Close()
+
+		err = app2.Run([]string{"", "-a=127.0.0.1", "-p=password", "--cache-dns=1m", "help"})
+		assert.EqualError(t, err, "cli: execute: auth: resolve: address 127.0.0.1: missing port in address")
+	})
+
+	// Test limiting response lines.
+	t.Run("response lines", func(t *testing.T) {
+		for args, expected := range map[string]string{
+			"--lines=2":                "-admin\n-player\n",
+			"--response-limit-lines=1": "Players connected (2):\n[...2 more lines]\n",
+			"--response-limit-lines=5": "Players connected (2):\n-admin\n-player\n",
+		} {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+
+			err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", args, "players"})
+			assert.NoError(t, err)
+			assert.Equal(t, expected, w.String(), args)
+
+			app.Close()
+		}
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--lines=2", "--response-limit-lines=1", "players"})
+		assert.EqualError(t, err, "cli: mutually exclusive flags: --lines and --response-limit-lines")
+	})
+
+	// Test script subcommand.
+	t.Run("script", func(t *testing.T) {
+		scriptFileName := filepath.Join(t.TempDir(), "players.tengo")
+		createFile(scriptFileName, "fmt := import(\"fmt\")\ntext := import(\"text\")\n"+
+			"response := rcon.execute(\"echo \" + text.to_upper(\"hi\"))\nif response == \"HI\" {\n\tfmt.println(\"got \" + response)\n}\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "script", scriptFileName})
+		assert.NoError(t, err)
+		assert.Equal(t, "got HI\n", w.String())
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "script", scriptFileName, "extra"})
+		assert.ErrorIs(t, err, executor.ErrInvalidArguments)
+	})
+
+	// Test wrapping response lines at word boundaries.
+	t.Run("response wrap", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--response-wrap=10", "players"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Players\nconnected\n(2):\n-admin\n-player\n", w.String())
+	})
+
+	// Test pre and post command hooks.
+	t.Run("command hooks", func(t *testing.T) {
+		hookFileName := filepath.Join(t.TempDir(), "hook.log")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, `--pre-command=test "$RCON_CMD" != players`)
+		args = append(args, `--post-command=echo "$RCON_CMD: $RCON_RESPONSE" >> `+hookFileName)
+		args = append(args, "help", "players")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n"+executor.CommandsResponseSeparator+"\npre command: exit status 1, skip players\n", w.String())
+
+		hooks, err := os.ReadFile(hookFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "help: Can I help you?\n", string(hooks))
+	})
+
+	// Test session start and end hooks.
+	t.Run("session hooks", func(t *testing.T) {
+		hookFileName := filepath.Join(t.TempDir(), "hook.log")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-e=prod",
+			`--on-start=echo "start $RCON_ADDRESS $RCON_ENV" >> ` + hookFileName,
+			`--on-end=echo "end $RCON_ENV" >> ` + hookFileName, "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		app = executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=wrong",
+			`--on-end=echo "end $RCON_ENV" >> ` + hookFileName, "help"})
+		assert.Error(t, err)
+
+		hooks, err := os.ReadFile(hookFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "start "+serverRCON.Addr()+" prod\nend prod\nend default\n", string(hooks))
+
+		w.Reset()
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--on-start=exit 1", "help"})
+		assert.EqualError(t, err, "cli: on start: exit status 1")
+		assert.Empty(t, w.String())
+	})
+
+	// Test identical consecutive responses are replaced with unchanged mark.
+	t.Run("response dedup", func(t *testing.T) {
+		hookFileName := filepath.Join(t.TempDir(), "hook.log")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--response-dedup",
+			`--dedup-on-change-exec=echo "$RCON_CMD: $RCON_RESPONSE" >> ` + hookFileName, "help", "help", "echo hi"})
+		assert.NoError(t, err)
+
+		lines := strings.Split(w.String(), "\n")
+		if assert.Len(t, lines, 6) {
+			assert.Equal(t, "Can I help you?", lines[0])
+			assert.Regexp(t, `^`+regexp.QuoteMeta(executor.UnchangedResponse)+` \d{4}-\d{2}-\d{2}T`, lines[2])
+			assert.Equal(t, "hi", lines[4])
+		}
+
+		hooks, err := os.ReadFile(hookFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "echo hi: hi\n", string(hooks))
+	})
+
+	// Test buffered output is flushed after each response.
+	t.Run("disable buffering", func(t *testing.T) {
+		for flag, expected := range map[string]string{"--disable-buffering": "Can I help you?\n", "--skip": ""} {
+			w := &bytes.Buffer{}
+			buffered := bufio.NewWriter(w)
+
+			app := executor.NewExecutor(nil, buffered, "")
+
+			err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", flag, "help"})
+			assert.NoError(t, err)
+			assert.Equal(t, expected, w.String(), flag)
+
+			app.Close()
+		}
+
+		// Each response is flushed before the next command is executed.
+		w := &recordWriter{}
+
+		app := executor.NewExecutor(nil, bufio.NewWriter(w), "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--disable-buffering", "echo one", "echo two"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"one\n", "--------\n", "two\n"}, w.writes)
+	})
+
+	// Test output throttling.
+	t.Run("throttle", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		start := time.Now()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--throttle=20", "players"})
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+		assert.Equal(t, "Players connected (2):\n-admin\n-player\n", w.String())
+	})
+
+	// Test printing resolved session without connecting.
+	t.Run("dry run show config", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a=127.0.0.2:12345")
+		args = append(args, "-p="+"password")
+		args = append(args, "--dry-run-show-config")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), `"address": "127.0.0.2:12345"`)
+		assert.Contains(t, w.String(), `"password": "`+config.PasswordMask+`"`)
+		assert.NotContains(t, w.String(), "Can I help you?")
+	})
+
+	// Test converting response line endings.
+	t.Run("response newline", func(t *testing.T) {
+		logFileName := "rcon-test-newline.log"
+		defer os.Remove(logFileName)
+
+		for mode, expected := range map[string]string{
+			executor.NewlineAuto: "Players connected (2):\n-admin\n-player\n",
+			executor.NewlineLF:   "Players connected (2):\n-admin\n-player\n",
+			executor.NewlineCRLF: "Players connected (2):\r\n-admin\r\n-player\n",
+		} {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+
+			args := os.Args[0:1]
+			args = append(args, "-a="+serverRCON.Addr())
+			args = append(args, "-p="+"password")
+			args = append(args, "-l="+logFileName)
+			args = append(args, "--response-newline="+mode)
+			args = append(args, "players")
+
+			err := app.Run(args)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, w.String(), mode)
+
+			app.Close()
+		}
+
+		log, err := os.ReadFile(logFileName)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(log), "\r")
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--response-newline=cr", "players"})
+		assert.EqualError(t, err, "cli: response newline: unsupported newline cr")
+	})
+
+	// Test response trim modes.
+	t.Run("trim", func(t *testing.T) {
+		for args, expected := range map[string]string{
+			"--trim=whitespace":        "padded\n",
+			"--trim=trailing-newline":  "  padded\n",
+			"--strip-trailing-newline": "  padded\n",
+			"--trim=none":              "  padded\n\n",
+		} {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+
+			err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", args, "echo   padded\r\n"})
+			assert.NoError(t, err)
+			assert.Equal(t, expected, w.String(), args)
+
+			app.Close()
+		}
+
+		for _, args := range []string{"--trim=newlines", "--keep-empty-lines"} {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+
+			err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", args, "echo \r\n  padded\n\nline \r\n"})
+			assert.NoError(t, err)
+			assert.Equal(t, "  padded\n\nline \n", w.String(), args)
+
+			app.Close()
+		}
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--trim=all", "help"})
+		assert.EqualError(t, err, "cli: trim: unsupported trim mode all")
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--strip-trailing-newline",
+			"--keep-empty-lines", "help"})
+		assert.ErrorIs(t, err, executor.ErrFlagsConflict)
+	})
+
+	// Test assembling address from address and port flags.
+	t.Run("port", func(t *testing.T) {
+		host, port, err := net.SplitHostPort(serverRCON.Addr())
+		assert.NoError(t, err)
+
+		for _, address := range []string{host, host + ":1", "[" + host + "]"} {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+
+			err := app.Run([]string{"", "-a=" + address, "--port=" + port, "-p=password", "help"})
+			assert.NoError(t, err, address)
+			assert.Equal(t, "Can I help you?\n", w.String(), address)
+
+			app.Close()
+		}
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err = app.Run([]string{"", "-a=" + host, "--port=70000", "-p=password", "help"})
+		assert.EqualError(t, err, "cli: invalid address: port 70000 is out of range")
+
+		err = app.Run([]string{"", "-a=:16260", "--port=" + port, "-p=password", "help"})
+		assert.EqualError(t, err, "cli: invalid address: :16260")
+	})
+
+	// Test HTTP proxy validation.
+	t.Run("http proxy", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--http-proxy=socks5://127.0.0.1:1080", "help"})
+		assert.ErrorIs(t, err, proxy.ErrInvalidURL)
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-t=battleye",
+			"--http-proxy=http://127.0.0.1:3128", "help"})
+		assert.EqualError(t, err, "cli: execute: auth: proxy is not supported by battleye protocol")
+	})
+
+	// Test dialing rewritten address while logging the original one.
+	t.Run("rewrite rules", func(t *testing.T) {
+		dir := t.TempDir()
+		configFileName := filepath.Join(dir, "rcon.yaml")
+		logName := filepath.Join(dir, "rcon.log")
+		createFile(configFileName, fmt.Sprintf("default:\n  address: 192.0.2.1:16260\n  password: password\n  log: %s\n"+
+			"  rewrite_rules:\n    192.0.2.1:16260: %s\n", logName, serverRCON.Addr()))
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName, "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		data, err := os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "192.0.2.1:16260: help")
+	})
+
+	// Test rewriting commands by expression.
+	t.Run("command rewrite", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", `--command-rewrite="echo " + upper(command)`,
+			"hello"})
+		assert.NoError(t, err)
+		assert.Equal(t, "HELLO\n", w.String())
+
+		w.Reset()
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password",
+			`--command-rewrite=command == "h" ? "help" : command`, "h"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--command-rewrite=upper(", "help"})
+		assert.EqualError(t, err, `cli: command rewrite: invalid expression "upper(": unexpected token EOF`)
+	})
+
+	// Test extracting values from JSON responses.
+	t.Run("response json path", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--response-json-path=$.players[*].name",
+			`echo {"players": [{"name": "admin"}, {"name": "player"}]}`})
+		assert.NoError(t, err)
+		assert.Equal(t, "admin\nplayer\n", w.String())
+
+		w.Reset()
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--response-json-path=$.players", "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "response json path: decode: invalid character 'C' looking for beginning of value\n", w.String())
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--response-json-path=players", "help"})
+		assert.EqualError(t, err, `cli: response json path: invalid json path "players": must start with $`)
+	})
+
+	// Test command encoding.
+	t.Run("command encoding", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--command-encoding=latin1", "echo café"})
+		assert.NoError(t, err)
+		assert.Equal(t, "caf\xe9\n", w.String())
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--command-encoding=pigeon", "help"})
+		assert.EqualError(t, err, "cli: command encoding: unknown encoding pigeon")
+	})
+
+	// Test unknown syslog facility.
+	t.Run("unknown syslog facility", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--log-syslog", "--syslog-facility=pigeon", "help"})
+		assert.EqualError(t, err, "cli: log: unsupported syslog facility pigeon")
+	})
+
+	// Test header line in new log file.
+	t.Run("log include headers", func(t *testing.T) {
+		logName := filepath.Join(t.TempDir(), "rcon.log")
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		for i := 0; i < 2; i++ {
+			err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-l=" + logName, "--log-include-headers", "help"})
+			assert.NoError(t, err)
+		}
+
+		data, err := os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Regexp(t, `^# rcon-cli log started at \S+ address=`+regexp.QuoteMeta(serverRCON.Addr())+` protocol=rcon\n\n\[`,
+			string(data))
+		assert.Equal(t, 1, strings.Count(string(data), "# rcon-cli log started"))
+	})
+
+	// Test resending commands to overloaded server.
+	t.Run("busy pattern", func(t *testing.T) {
+		var requests atomic.Int32
+
+		serverBusy := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				response := "Can I help you?"
+				if requests.Add(1) <= 2 {
+					response = "Too many requests"
+				}
+
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, response).WriteTo(c.Conn())
+			}),
+		)
+		defer serverBusy.Close()
+
+		for retries, expected := range map[string]string{"2": "Can I help you?\n", "1": "Too many requests\n"} {
+			requests.Store(0)
+
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+
+			err := app.Run([]string{"", "-a=" + serverBusy.Addr(), "-p=password", "--busy-pattern=^Too many",
+				"--max-busy-retries=" + retries, "--busy-delay=1ms", "help"})
+			if retries == "1" {
+				assert.ErrorIs(t, err, executor.ErrServerBusy)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, expected, w.String(), retries)
+
+			app.Close()
+		}
+	})
+
+	// Test reading the command from environment variable.
+	t.Run("command from env", func(t *testing.T) {
+		t.Setenv("RCON_TEST_COMMAND", "echo from env")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--command-from-env=RCON_TEST_COMMAND"})
+		assert.NoError(t, err)
+		assert.Equal(t, "from env\n", w.String())
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--command-from-env=RCON_TEST_COMMAND", "help"})
+		assert.ErrorIs(t, err, executor.ErrFlagsConflict)
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--command-from-env=RCON_TEST_UNSET"})
+		assert.EqualError(t, err, "cli: command is not set: RCON_TEST_UNSET environment variable is empty")
+	})
+
+	// Test config is reloaded in watch mode and new password is used.
+	t.Run("watch config reload", func(t *testing.T) {
+		configFileName := filepath.Join(t.TempDir(), "rcon.yaml")
+		layout := "default:\n  address: %s\n  password: %s\n"
+		createFile(configFileName, fmt.Sprintf(layout, serverRCON.Addr(), "password"))
+
+		w := &hookWriter{hook: func() {
+			createFile(configFileName, fmt.Sprintf(layout, serverRCON.Addr(), "wrong"))
+		}}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		// Watch count bounds the time to receive the write event.
+		err := app.Run([]string{"", "-c=" + configFileName, "--config-watch", "--watch=10ms", "--watch-count=500", "help"})
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+		assert.True(t, strings.HasPrefix(w.String(), "Can I help you?\n"))
+	})
+
+	// Test repeated execution with clearing when output is a terminal.
+	t.Run("watch", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--watch=10ms", "--watch-count=2", "echo one"})
+		assert.NoError(t, err)
+		assert.Equal(t, "one\n"+executor.WatchSeparator+"\none\n", w.String())
+
+		w.Reset()
+
+		ses := config.Session{Address: serverRCON.Addr(), Password: "password", Watch: 10 * time.Millisecond,
+			WatchCount: 2, WatchClear: true}
+		err = app.Watch(w, &ses, []string{"echo one"})
+		assert.NoError(t, err)
+		assert.Equal(t, executor.ClearScreen+"one\n"+executor.ClearScreen+"one\n", w.String())
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--watch-clear", "--watch-no-clear", "help"})
+		assert.ErrorIs(t, err, executor.ErrFlagsConflict)
+	})
+
+	// Test prefix is removed from the first line only.
+	t.Run("response trim prefix", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--response-trim-prefix=[OK] ",
+			"echo [OK] saved\n[OK] done"})
+		assert.NoError(t, err)
+		assert.Equal(t, "saved\n[OK] done\n", w.String())
+	})
+
+	// Test NUL-terminated responses of several commands.
+	t.Run("newline separator", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", `--newline-separator=\0`, "echo one", "echo two"})
+		assert.NoError(t, err)
+		assert.Equal(t, "one\x00two\x00", w.String())
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", `--newline-separator=\q`, "help"})
+		assert.EqualError(t, err, "cli: newline separator: invalid syntax")
+	})
+
+	// Test prefix of every response line in output and log.
+	t.Run("response prefix", func(t *testing.T) {
+		logName := filepath.Join(t.TempDir(), "rcon.log")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-l=" + logName,
+			"--response-prefix=[RCON] ", "--lines=2", "players"})
+		assert.NoError(t, err)
+		assert.Equal(t, "[RCON] -admin\n[RCON] -player\n", w.String())
+
+		data, err := os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "[RCON] Players connected (2):\n[RCON] -admin\n[RCON] -player\n")
+	})
+
+	// Test log file is flushed after every entry.
+	t.Run("log sync", func(t *testing.T) {
+		logName := filepath.Join(t.TempDir(), "rcon.log")
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-l=" + logName, "--log-sync", "help"})
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "Can I help you?")
+	})
+
+	// Test patterns are masked in log entries.
+	t.Run("log mask pattern", func(t *testing.T) {
+		logName := filepath.Join(t.TempDir(), "rcon.log")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-l=" + logName,
+			`--log-mask-pattern=\d{1,3}(\.\d{1,3}){3}`, "--log-mask-pattern=-admin", "echo -admin 10.0.0.1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "-admin 10.0.0.1\n", w.String())
+
+		data, err := os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), ": echo **** ****\n**** ****\n")
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-l=" + logName,
+			"--log-mask-pattern=(echo) .*", "--log-mask-replacement=$1 [hidden]", "echo secret"})
+		assert.NoError(t, err)
+
+		data, err = os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), ": echo [hidden]\nsecret\n")
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--log-mask-pattern=(", "help"})
+		assert.Error(t, err)
+	})
+
+	// Test command latency is written to log entries.
+	t.Run("write latency", func(t *testing.T) {
+		logName := filepath.Join(t.TempDir(), "rcon.log")
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-l=" + logName, "--write-latency", "help"})
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Regexp(t, `: help \(latency \d+ms\)\nCan I help you\?`, string(data))
+	})
+
+	// Test gzip compressed log file.
+	t.Run("compress log", func(t *testing.T) {
+		logName := filepath.Join(t.TempDir(), "rcon.log")
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-l=" + logName, "--compress-log", "help"})
+		assert.NoError(t, err)
+		assert.NoFileExists(t, logName)
+
+		file, err := os.Open(logName + ".gz")
+		assert.NoError(t, err)
+
+		defer file.Close()
+
+		r, err := gzip.NewReader(file)
+		assert.NoError(t, err)
+
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), ": help\nCan I help you?\n\n")
+	})
+
+	// Test log entries sent to UDP log aggregator.
+	t.Run("log udp", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		assert.NoError(t, err)
+
+		defer conn.Close()
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--log-udp=" + conn.LocalAddr().String(), "help"})
+		assert.NoError(t, err)
+
+		buf := make([]byte, 1024)
+
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+		n, _, err := conn.ReadFrom(buf)
+		assert.NoError(t, err)
+		assert.Contains(t, string(buf[:n]), `"request":"help","response":"Can I help you?"`)
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--log-udp=127.0.0.1", "help"})
+		assert.EqualError(t, err, "cli: log: udp: address 127.0.0.1: missing port in address")
+	})
+
+	// Test command line terminator.
+	t.Run("command newline", func(t *testing.T) {
+		for mode, expected := range map[string]string{
+			"lf":   `"hi\n"`,
+			"crlf": `"hi\r\n"`,
+			"cr":   `"hi\r"`,
+		} {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+
+			err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--command-newline=" + mode, "quote hi"})
+			assert.NoError(t, err)
+			assert.Equal(t, expected+"\n", w.String(), mode)
+
+			app.Close()
+		}
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "--command-newline=nl", "help"})
+		assert.EqualError(t, err, "cli: command newline: unsupported newline nl")
+
+		err = app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-t=telnet", "--command-newline=lf", "help"})
+		assert.EqualError(t, err, "cli: execute: auth: command newline is not supported by telnet protocol")
+	})
+
+	// Test unknown response encoding.
+	t.Run("unknown response encoding", func(t *testing.T) {
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--response-encoding=pigeon")
 		args = append(args, "help")
 
 		err := app.Run(args)
-		assert.EqualError(t, err, "cli: password is not set: to set password add -p password")
+		assert.EqualError(t, err, "cli: response encoding: unknown encoding pigeon")
+	})
+
+	// Test config environment credentials check.
+	t.Run("config env test", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "zomboid", serverRCON.Addr(), "password", "", "") + "\n" +
+			fmt.Sprintf(ConfigLayoutYAML, "wrong", serverRCON.Addr(), "fake", "", "")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName, "config", "env", "test", "zomboid"})
+		assert.NoError(t, err)
+		assert.Equal(t, "OK\n", w.String())
+
+		err = app.Run([]string{"", "-c=" + configFileName, "config", "env", "test", "wrong"})
+		assert.EqualError(t, err, "cli: wrong: auth: rcon: authentication failed")
+
+		err = app.Run([]string{"", "-c=" + configFileName, "config", "env", "test", "rust"})
+		assert.EqualError(t, err, "cli: config: environment not found: rust")
+	})
+
+	// Test diff of config environments.
+	t.Run("config diff", func(t *testing.T) {
+		configFileName := filepath.Join(t.TempDir(), "rcon.yaml")
+		createFile(configFileName, "prod:\n  address: 127.0.0.1:16260\n  password: secret\n"+
+			"staging:\n  address: 127.0.0.1:16261\n  password: password\n  type: web\n"+
+			"copy:\n  address: 127.0.0.1:16260\n  password: secret\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName, "config", "diff", "prod", "staging"})
+		assert.NoError(t, err)
+		assert.Equal(t, "address: 127.0.0.1:16260 -> 127.0.0.1:16261\npassword: **** -> ****\ntype: \"\" -> web\n", w.String())
+
+		w.Reset()
+
+		err = app.Run([]string{"", "-c=" + configFileName, "config", "diff", "--show-secrets", "prod", "staging"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "password: secret -> password\n")
+
+		w.Reset()
+
+		err = app.Run([]string{"", "-c=" + configFileName, "config", "diff", "prod", "copy"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Environments prod and copy are identical\n", w.String())
+
+		err = app.Run([]string{"", "-c=" + configFileName, "config", "diff", "prod", "unknown"})
+		assert.EqualError(t, err, "cli: config: environment not found: unknown")
+
+		err = app.Run([]string{"", "-c=" + configFileName, "config", "diff", "prod"})
+		assert.EqualError(t, err, "cli: invalid arguments: expected <env1> <env2>")
+	})
+
+	// Test config file rendered as template with environment variables.
+	t.Run("config template", func(t *testing.T) {
+		t.Setenv("RCON_TEST_PASSWORD", "password")
+
+		configFileName := filepath.Join(t.TempDir(), "rcon.yaml")
+		createFile(configFileName, "default:\n  address: "+serverRCON.Addr()+"\n  password: \"{{ .Env.RCON_TEST_PASSWORD }}\"\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName, "--config-template", "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+	})
+
+	// Test export of config environment as shell script.
+	t.Run("config export", func(t *testing.T) {
+		configFileName := filepath.Join(t.TempDir(), "rcon.yaml")
+		createFile(configFileName, "prod:\n  address: 127.0.0.1:16260\n  password: it's \\secret\n  type: web\n")
+
+		for format, expected := range map[string]string{
+			"sh":         "export RCON_ADDRESS='127.0.0.1:16260'\nexport RCON_PASSWORD='it'\\''s \\secret'\nexport RCON_TYPE='web'\n",
+			"fish":       "set -gx RCON_ADDRESS '127.0.0.1:16260'\nset -gx RCON_PASSWORD 'it\\'s \\\\secret'\nset -gx RCON_TYPE 'web'\n",
+			"powershell": "$env:RCON_ADDRESS = '127.0.0.1:16260'\n$env:RCON_PASSWORD = 'it''s \\secret'\n$env:RCON_TYPE = 'web'\n",
+		} {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+
+			err := app.Run([]string{"", "-c=" + configFileName, "config", "export", "--format=" + format, "prod"})
+			assert.NoError(t, err, format)
+			assert.Equal(t, expected, w.String(), format)
+
+			app.Close()
+		}
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName, "config", "export", "--redact", "--prefix=game", "prod"})
+		assert.NoError(t, err)
+		assert.Equal(t, "export GAME_ADDRESS='127.0.0.1:16260'\nexport GAME_TYPE='web'\n", w.String())
+
+		err = app.Run([]string{"", "-c=" + configFileName, "config", "export", "--format=cmd", "prod"})
+		assert.ErrorIs(t, err, executor.ErrUnsupportedExportFormat)
+
+		err = app.Run([]string{"", "-c=" + configFileName, "config", "export", "rust"})
+		assert.EqualError(t, err, "cli: config: environment not found: rust")
+	})
+
+	// Test connection details from environment variables.
+	t.Run("env prefix", func(t *testing.T) {
+		t.Setenv("RCON_ADDRESS", serverRCON.Addr())
+		t.Setenv("RCON_PASSWORD", "password")
+		t.Setenv("PROD_RCON_ADDRESS", serverRCON.Addr())
+		t.Setenv("PROD_RCON_PASSWORD", "wrong")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		app = executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err = app.Run([]string{"", "--env-prefix=prod_rcon", "help"})
+		assert.EqualError(t, err, "cli: execute: auth: rcon: authentication failed")
+	})
+
+	// Test import of config environments from environment variables.
+	t.Run("config env import", func(t *testing.T) {
+		t.Setenv("RCONTEST_PROD_ADDRESS", "127.0.0.1:16260")
+		t.Setenv("RCONTEST_PROD_PASSWORD", "secret")
+
+		configFileName := filepath.Join(t.TempDir(), "rcon.yaml")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName, "config", "env", "import", "--prefix=RCONTEST", "--dry-run"})
+		assert.NoError(t, err)
+		assert.Equal(t, "prod:\n    address: 127.0.0.1:16260\n    password: '****'\n", w.String())
+		assert.NoFileExists(t, configFileName)
+
+		w.Reset()
+
+		err = app.Run([]string{"", "-c=" + configFileName, "config", "env", "import", "--prefix=RCONTEST"})
+		assert.NoError(t, err)
+		assert.Equal(t, "prod: 127.0.0.1:16260 (rcon)\n", w.String())
+
+		cfg, err := config.NewConfig(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", (*cfg)["prod"].Password)
+
+		err = app.Run([]string{"", "-c=" + configFileName, "config", "env", "import", "--prefix=NOTHING"})
+		assert.EqualError(t, err, "cli: config: environment not found: no variables with NOTHING_ prefix")
+	})
+
+	// Test profiles with config file and environment.
+	t.Run("profile", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "zomboid", serverRCON.Addr(), "password", "", "")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "profile", "add", "pz", configFileName, "zomboid"})
+		assert.NoError(t, err)
+
+		err = app.Run([]string{"", "profile", "add", "pz", configFileName})
+		assert.EqualError(t, err, "cli: profile: profile already exists: pz")
+
+		w.Reset()
+
+		err = app.Run([]string{"", "--profile=pz", "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		err = app.Run([]string{"", "--profile=rust", "help"})
+		assert.EqualError(t, err, "cli: profile: profile not found: rust")
+
+		w.Reset()
+
+		err = app.Run([]string{"", "profile", "remove", "pz"})
+		assert.NoError(t, err)
+
+		err = app.Run([]string{"", "profile", "list"})
+		assert.NoError(t, err)
+		assert.Equal(t, "", w.String())
+	})
+
+	// Test writing PID file.
+	t.Run("write pid", func(t *testing.T) {
+		pidFileName := "rcon-test.pid"
+		defer os.Remove(pidFileName)
+
+		handler := func(c *rcontest.Context) {
+			pid, err := os.ReadFile(pidFileName)
+			assert.NoError(t, err)
+			assert.Equal(t, strconv.Itoa(os.Getpid())+"\n", string(pid))
+
+			handlersRCON(c)
+		}
+
+		server := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(handler),
+		)
+		defer server.Close()
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + server.Addr(), "-p=password", "--write-pid=" + pidFileName, "help"})
+		assert.NoError(t, err)
+		assert.NoFileExists(t, pidFileName)
+	})
+
+	// Test writing support bundle.
+	t.Run("diagnostics", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "zomboid", serverRCON.Addr(), "password", "", "")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		bundleFileName := filepath.Join(t.TempDir(), "bundle.zip")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName, "-p=secret", "diagnostics", "--out=" + bundleFileName})
+		assert.NoError(t, err)
+		assert.Equal(t, "Diagnostics bundle written to "+bundleFileName+"\n", w.String())
+
+		archive, err := zip.OpenReader(bundleFileName)
+		assert.NoError(t, err)
+
+		defer archive.Close()
+
+		r, err := archive.Open("flags.txt")
+		assert.NoError(t, err)
+
+		flags, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Contains(t, string(flags), "config="+configFileName+"\n")
+		assert.Contains(t, string(flags), "p=****\n")
+		assert.NotContains(t, string(flags), "secret")
+	})
+
+	// Test printing the last log entries.
+	t.Run("log tail", func(t *testing.T) {
+		logFileName := "rcon-test-tail.log"
+		defer os.Remove(logFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-a=" + serverRCON.Addr(), "-p=password", "-l=" + logFileName, "help", "players"})
+		assert.NoError(t, err)
+
+		w.Reset()
+
+		err = app.Run([]string{"", "log", "tail", "--file=" + logFileName, "-n=1"})
+		assert.NoError(t, err)
+		assert.Regexp(t, `^\[.+\] `+regexp.QuoteMeta(serverRCON.Addr())+`: players\nPlayers connected \(2\):\n-admin\n-player\n$`, w.String())
+	})
+
+	// Test session transcript with tee.
+	t.Run("tee", func(t *testing.T) {
+		teeFileName := "rcon-test-tee.log"
+		defer os.Remove(teeFileName)
+
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--tee="+teeFileName)
+
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+
+		transcript, err := os.ReadFile(teeFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, w.String(), string(transcript))
+		assert.Contains(t, string(transcript), "> Can I help you?")
 	})
 
 	// Positive test Interactive. Log is not used.
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// Shell syntaxes of config export subcommand.
+const (
+	ExportFormatSh         = "sh"
+	ExportFormatFish       = "fish"
+	ExportFormatPowerShell = "powershell"
+)
+
+// ValidateExportFormat checks the shell syntax of exported variables.
+func ValidateExportFormat(format string) error {
+	switch format {
+	case "", ExportFormatSh, ExportFormatFish, ExportFormatPowerShell:
+		return nil
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedExportFormat, format)
+	}
+}
+
+// WriteExports writes the script which sets <PREFIX>_ADDRESS,
+// <PREFIX>_PASSWORD, <PREFIX>_TYPE and <PREFIX>_LOG environment variables
+// from the session in the shell format. Empty fields are skipped, password is
+// skipped if redact is true.
+func WriteExports(w io.Writer, ses *config.Session, prefix string, format string, redact bool) {
+	vars := [][2]string{{"ADDRESS", ses.Address}, {"PASSWORD", ses.Password}, {"TYPE", ses.Type}, {"LOG", ses.Log}}
+
+	for _, v := range vars {
+		if v[1] == "" || (redact && v[0] == "PASSWORD") {
+			continue
+		}
+
+		name := prefix + "_" + v[0]
+
+		switch format {
+		case ExportFormatFish:
+			_, _ = fmt.Fprintf(w, "set -gx %s %s\n", name, quoteFish(v[1]))
+		case ExportFormatPowerShell:
+			_, _ = fmt.Fprintf(w, "$env:%s = %s\n", name, quotePowerShell(v[1]))
+		default:
+			_, _ = fmt.Fprintf(w, "export %s=%s\n", name, quoteSh(v[1]))
+		}
+	}
+}
+
+// quoteSh returns s in single quotes. Single quotes can not be escaped
+// inside single quotes, so they are closed, escaped and opened again.
+func quoteSh(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteFish returns s in single quotes with escaped backslashes and single
+// quotes.
+func quoteFish(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// quotePowerShell returns s in single quotes with doubled single quotes.
+func quotePowerShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,57 @@
+package executor
+
+import "io"
+
+// flusher is implemented by buffered writers like bufio.Writer.
+type flusher interface {
+	Flush() error
+}
+
+// flushWriter flushes the underlying buffered writer after each write.
+// Responses are printed with a single write, so each response reaches the
+// pipeline as soon as it is received.
+type flushWriter struct {
+	w io.Writer
+}
+
+// newFlushWriter creates a new flushWriter.
+func newFlushWriter(w io.Writer) *flushWriter {
+	return &flushWriter{w: w}
+}
+
+// Write writes p to the underlying writer and flushes it.
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	return n, f.Flush()
+}
+
+// Flush flushes the underlying writer if it is buffered.
+func (f *flushWriter) Flush() error {
+	if w, ok := f.w.(flusher); ok {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// flushOutput flushes the executor writer if it is buffered.
+func (executor *Executor) flushOutput() {
+	if w, ok := executor.w.(flusher); ok {
+		_ = w.Flush()
+	}
+}
+
+// disableBuffering wraps executor writer with flushWriter. Returned function
+// restores the writer.
+func (executor *Executor) disableBuffering() func() {
+	w := executor.w
+	executor.w = newFlushWriter(w)
+
+	return func() {
+		executor.w = w
+	}
+}
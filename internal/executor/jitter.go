@@ -0,0 +1,40 @@
+package executor
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// jitter generates random delays between commands. Source is seeded with
+// crypto/rand so the delays are not predictable between runs.
+var jitter = struct {
+	sync.Mutex
+	rand *rand.Rand
+}{rand: rand.New(rand.NewSource(cryptoSeed()))} //nolint:gosec // Seeded with crypto/rand.
+
+// sleepJitter sleeps a uniform random duration in [0, limit].
+func sleepJitter(limit time.Duration) {
+	if limit <= 0 {
+		return
+	}
+
+	jitter.Lock()
+	delay := time.Duration(jitter.rand.Int63n(int64(limit) + 1))
+	jitter.Unlock()
+
+	time.Sleep(delay)
+}
+
+// cryptoSeed returns random seed from crypto/rand. Falls back to current time
+// if crypto/rand is not available.
+func cryptoSeed() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+
+	return int64(binary.LittleEndian.Uint64(b[:]))
+}
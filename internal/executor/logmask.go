@@ -0,0 +1,39 @@
+package executor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultLogMaskReplacement replaces matches of --log-mask-pattern in log
+// entries.
+const DefaultLogMaskReplacement = "****"
+
+// patternsValue is repeatable flag of regular expressions. Unlike
+// StringSliceFlag values are not split by commas, which are common in
+// regular expressions like \d{1,3}.
+type patternsValue struct {
+	patterns []*regexp.Regexp
+}
+
+// Set compiles the regular expression and appends it to patterns.
+func (v *patternsValue) Set(value string) error {
+	pattern, err := regexp.Compile(value)
+	if err != nil {
+		return err
+	}
+
+	v.patterns = append(v.patterns, pattern)
+
+	return nil
+}
+
+// String returns comma separated patterns.
+func (v *patternsValue) String() string {
+	patterns := make([]string, len(v.patterns))
+	for i, pattern := range v.patterns {
+		patterns[i] = pattern.String()
+	}
+
+	return strings.Join(patterns, ", ")
+}
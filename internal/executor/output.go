@@ -0,0 +1,304 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/logger"
+	"github.com/gorcon/rcon-cli/internal/throttle"
+)
+
+// Stats output destinations.
+const (
+	StatsOutputStderr = "stderr"
+	StatsOutputStdout = "stdout"
+)
+
+// Response and command line endings.
+const (
+	NewlineAuto = "auto"
+	NewlineLF   = "lf"
+	NewlineCRLF = "crlf"
+	NewlineCR   = "cr"
+)
+
+// Response trim modes.
+const (
+	TrimNone            = "none"
+	TrimTrailingNewline = "trailing-newline"
+	TrimNewlines        = "newlines"
+	TrimWhitespace      = "whitespace"
+)
+
+// Response code lines which are printed before responses.
+const (
+	ResponseCodeOK  = "OK"
+	ResponseCodeErr = "ERR: "
+)
+
+// DefaultResponseCodeDelimiter is the default delimiter between the response
+// code and the response in escaped form.
+const DefaultResponseCodeDelimiter = `\n`
+
+// DefaultNewlineSeparator is the default terminator of responses in escaped
+// form.
+const DefaultNewlineSeparator = `\n`
+
+// StatsPrefix is the prefix of the stats line. It allows to strip stats
+// from the output easily.
+const StatsPrefix = "# stats:"
+
+// Stats contains timings of command execution. Connect includes address
+// resolution and the proxy handshake, Auth includes telnet negotiation and
+// WebSocket handshake. Both are zero if the connection is reused.
+type Stats struct {
+	Connect time.Duration
+	Auth    time.Duration
+	Command time.Duration
+}
+
+// String returns formatted stats line.
+func (stats Stats) String() string {
+	return fmt.Sprintf("%s connect=%s auth=%s cmd=%s total=%s", StatsPrefix,
+		stats.Connect.Round(time.Millisecond), stats.Auth.Round(time.Millisecond),
+		stats.Command.Round(time.Millisecond), (stats.Connect + stats.Auth + stats.Command).Round(time.Millisecond))
+}
+
+// Response contains the response details which are passed to the response
+// template.
+type Response struct {
+	Response  string
+	Command   string
+	Address   string
+	Timestamp time.Time
+	Duration  time.Duration
+}
+
+// ParseResponseTemplate compiles text/template to reformat responses.
+func ParseResponseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("response").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("response template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// ParseResponseCodeDelimiter unescapes delimiter given in Go string literal
+// form like \n or \x00.
+func ParseResponseCodeDelimiter(delimiter string) (string, error) {
+	unquoted, err := strconv.Unquote(`"` + delimiter + `"`)
+	if err != nil {
+		return "", fmt.Errorf("response code delimiter: %w", err)
+	}
+
+	return unquoted, nil
+}
+
+// ParseNewlineSeparator unescapes separator given in Go string literal form.
+// \0 is accepted as the NUL symbol for xargs -0.
+func ParseNewlineSeparator(separator string) (string, error) {
+	if separator == `\0` {
+		return "\x00", nil
+	}
+
+	unquoted, err := strconv.Unquote(`"` + separator + `"`)
+	if err != nil {
+		return "", fmt.Errorf("newline separator: %w", err)
+	}
+
+	return unquoted, nil
+}
+
+// responseTerminator returns the symbols written after each response.
+func responseTerminator(ses *config.Session) string {
+	if ses.NewlineSeparator != "" {
+		return ses.NewlineSeparator
+	}
+
+	return "\n"
+}
+
+// writeCommandsSeparator writes CommandsResponseSeparator line between
+// responses of several commands. Nothing is written if NewlineSeparator is
+// set because it terminates each response.
+func writeCommandsSeparator(w io.Writer, ses *config.Session) {
+	if ses.NewlineSeparator == "" {
+		_, _ = fmt.Fprintln(w, CommandsResponseSeparator)
+	}
+}
+
+// writeResponseCode prints OK or ERR with the error message followed by the
+// response code delimiter.
+func writeResponseCode(w io.Writer, ses *config.Session, err error) {
+	code := ResponseCodeOK
+	if err != nil {
+		code = ResponseCodeErr + err.Error()
+	}
+
+	_, _ = fmt.Fprint(w, code+ses.ResponseCodeDelimiter)
+}
+
+// ValidateNewline checks the response line ending mode.
+func ValidateNewline(mode string) error {
+	switch mode {
+	case "", NewlineAuto, NewlineLF, NewlineCRLF:
+		return nil
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedNewline, mode)
+	}
+}
+
+// ValidateCommandNewline checks the command line terminator mode.
+func ValidateCommandNewline(mode string) error {
+	switch mode {
+	case "", NewlineLF, NewlineCRLF, NewlineCR:
+		return nil
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedNewline, mode)
+	}
+}
+
+// ValidateTrim checks the response trim mode.
+func ValidateTrim(mode string) error {
+	switch mode {
+	case "", TrimNone, TrimTrailingNewline, TrimNewlines, TrimWhitespace:
+		return nil
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedTrim, mode)
+	}
+}
+
+// trimResponse trims str according to the trim mode. TrimWhitespace is used
+// when mode is empty.
+func trimResponse(str string, mode string) string {
+	switch mode {
+	case TrimNone:
+		return str
+	case TrimTrailingNewline:
+		return strings.TrimRight(str, "\r\n")
+	case TrimNewlines:
+		return strings.Trim(str, "\r\n")
+	default:
+		return strings.TrimSpace(str)
+	}
+}
+
+// commandTerminator returns line terminator appended to commands. Telnet
+// library appends "\r\n" itself.
+func commandTerminator(ses *config.Session) string {
+	if ses.Type == config.ProtocolTELNET {
+		return ""
+	}
+
+	switch ses.CommandNewline {
+	case NewlineLF:
+		return "\n"
+	case NewlineCRLF:
+		return "\r\n"
+	case NewlineCR:
+		return "\r"
+	default:
+		return ""
+	}
+}
+
+// normalizeNewlines converts line endings in str. NewlineAuto strips all
+// carriage returns, NewlineLF converts line endings to "\n" and NewlineCRLF
+// converts them to "\r\n".
+func normalizeNewlines(str string, mode string) string {
+	switch mode {
+	case NewlineLF:
+		return strings.ReplaceAll(strings.ReplaceAll(str, "\r\n", "\n"), "\r", "\n")
+	case NewlineCRLF:
+		return strings.ReplaceAll(normalizeNewlines(str, NewlineLF), "\n", "\r\n")
+	default:
+		return strings.ReplaceAll(str, "\r", "")
+	}
+}
+
+// tailLines returns the last n lines of str.
+func tailLines(str string, n int) string {
+	lines := strings.Split(str, "\n")
+	if len(lines) <= n {
+		return str
+	}
+
+	return strings.Join(lines[len(lines)-n:], "\n")
+}
+
+// limitLines returns the first n lines of str and the number of omitted
+// lines note.
+func limitLines(str string, n int) string {
+	lines := strings.Split(str, "\n")
+	if len(lines) <= n {
+		return str
+	}
+
+	return strings.Join(lines[:n], "\n") + fmt.Sprintf("\n[...%d more lines]", len(lines)-n)
+}
+
+// prefixLines prepends prefix to every line of str. Empty str is returned as
+// is.
+func prefixLines(str string, prefix string) string {
+	if prefix == "" || str == "" {
+		return str
+	}
+
+	return prefix + strings.ReplaceAll(str, "\n", "\n"+prefix)
+}
+
+// printResponse writes the response to w. The response is rendered with
+// the response template if it is set.
+func (executor *Executor) printResponse(w io.Writer, ses *config.Session, response *Response) {
+	if ses.ResponseTemplate != nil {
+		if err := ses.ResponseTemplate.Execute(w, response); err != nil {
+			_, _ = fmt.Fprintln(w, fmt.Errorf("response template: %w", err))
+
+			return
+		}
+
+		_, _ = fmt.Fprint(w, responseTerminator(ses))
+
+		return
+	}
+
+	_, _ = fmt.Fprint(w, response.Response+responseTerminator(ses))
+}
+
+// throttle wraps executor writer with throttle.Writer which limits output
+// to rate lines per second. Returned function restores the writer.
+func (executor *Executor) throttle(rate float64) func() {
+	w := executor.w
+	executor.w = throttle.NewWriter(w, rate)
+
+	return func() {
+		executor.w = w
+	}
+}
+
+// printStats writes stats line to the StatsOutput destination.
+func (executor *Executor) printStats(w io.Writer, ses *config.Session, stats Stats) {
+	switch ses.StatsOutput {
+	case "", StatsOutputStderr:
+		_, _ = fmt.Fprintln(os.Stderr, stats)
+	case StatsOutputStdout:
+		_, _ = fmt.Fprintln(w, stats)
+	default:
+		file, err := logger.OpenFile(ses.StatsOutput)
+		if err != nil {
+			_, _ = fmt.Fprintln(w, fmt.Errorf("stats: %w", err))
+
+			return
+		}
+		defer file.Close()
+
+		_, _ = fmt.Fprintln(file, stats)
+	}
+}
@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// Commands of paste mode in Interactive mode. CommandPaste starts collecting
+// lines, CommandPasteEnd sends collected lines as one command and
+// CommandNoPaste returns to line by line mode.
+const (
+	CommandPaste    = ":paste"
+	CommandNoPaste  = ":nopaste"
+	CommandPasteEnd = ":end"
+)
+
+// paste collects lines of pasted block in paste mode.
+type paste struct {
+	enabled bool
+	lines   []string
+}
+
+// scan handles line read in Interactive mode. It returns the command to
+// execute and true if the command is a pasted block. Empty command is
+// returned while the block is collected.
+func (p *paste) scan(w io.Writer, ses *config.Session, line string) (string, bool) {
+	switch {
+	case line == CommandPaste:
+		p.enabled, p.lines = true, nil
+
+		if !ses.NoPrompt {
+			_, _ = fmt.Fprintf(w, "Paste mode, type %s to send the block or %s to exit\n", CommandPasteEnd, CommandNoPaste)
+		}
+
+		return "", false
+	case line == CommandNoPaste:
+		p.enabled, p.lines = false, nil
+
+		return "", false
+	case !p.enabled:
+		return line, false
+	case line == CommandPasteEnd:
+		block := strings.Join(p.lines, "\n")
+		p.lines = nil
+
+		return encodePaste(ses, block), true
+	default:
+		p.lines = append(p.lines, line)
+
+		return "", false
+	}
+}
+
+// encodePaste returns block in base64 if it contains characters which are
+// escaped or terminate commands in the session protocol: line endings in
+// telnet and NUL in rcon and BattlEye packets.
+func encodePaste(ses *config.Session, block string) string {
+	var escaped string
+
+	switch ses.Type {
+	case config.ProtocolTELNET:
+		escaped = "\r\n\x00"
+	case config.ProtocolWebRCON:
+		escaped = ""
+	default:
+		escaped = "\x00"
+	}
+
+	if escaped == "" || !strings.ContainsAny(block, escaped) {
+		return block
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(block))
+}
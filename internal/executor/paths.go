@@ -0,0 +1,42 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/gorcon/rcon-cli/internal/path"
+	"github.com/urfave/cli/v2"
+)
+
+// pathFlags contains names of global flags with file paths.
+var pathFlags = []string{
+	"config", "log", "tee", "file", "command-prefix-file", "history-file", "stats-output", "write-pid",
+	"checkpoint-file", "session-file", "address-file",
+}
+
+// before runs before any action. It expands file paths and writes PID file.
+func (executor *Executor) before(c *cli.Context) error {
+	if err := expandPaths(c); err != nil {
+		return err
+	}
+
+	return executor.writePID(c)
+}
+
+// expandPaths replaces a leading ~ in file path flags with the user home
+// directory because shells do not expand it in --flag=~/path form.
+func expandPaths(c *cli.Context) error {
+	for _, name := range pathFlags {
+		if !c.IsSet(name) {
+			continue
+		}
+
+		value := c.String(name)
+		if expanded := path.ExpandHome(value); expanded != value {
+			if err := c.Set(name, expanded); err != nil {
+				return fmt.Errorf("expand %s: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
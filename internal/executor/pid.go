@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+)
+
+// writePID writes the process PID to the --write-pid file. Existing file is
+// overwritten with a warning because it can be left after a crash.
+func (executor *Executor) writePID(c *cli.Context) error {
+	name := c.String("write-pid")
+	if name == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(name); err == nil {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: pid file %s already exists\n", name)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("pid file: %w", err)
+	}
+
+	const perm = 0o644
+
+	if err := os.WriteFile(name, []byte(strconv.Itoa(os.Getpid())+"\n"), perm); err != nil {
+		return fmt.Errorf("pid file: %w", err)
+	}
+
+	executor.pidFile = name
+
+	return nil
+}
+
+// removePID removes the PID file written on startup.
+func (executor *Executor) removePID() {
+	if executor.pidFile == "" {
+		return
+	}
+
+	_ = os.Remove(executor.pidFile)
+	executor.pidFile = ""
+}
@@ -0,0 +1,132 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// PipeSeparator is symbols that separates the remote command from the local
+// shell command in Interactive mode when pipe is enabled.
+const PipeSeparator = " | "
+
+// ErrPipeCommandEmpty is returned when the local side of the pipe is empty.
+var ErrPipeCommandEmpty = errors.New("pipe command is not set")
+
+// Pipe executes the left side of the command on the remote server and passes
+// the response as stdin to the local shell command from the right side.
+func (executor *Executor) Pipe(w io.Writer, ses *config.Session, command string) error {
+	remote, local, _ := strings.Cut(command, PipeSeparator)
+
+	local = strings.TrimSpace(local)
+	if local == "" {
+		return ErrPipeCommandEmpty
+	}
+
+	var response bytes.Buffer
+	if err := executor.Execute(&response, ses, strings.TrimSpace(remote)); err != nil {
+		return err
+	}
+
+	cmd := shellCommand(local)
+	cmd.Stdin = &response
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	if err := cmd.Run(); err != nil {
+		// Exit codes of local commands (e.g. grep without matches) must not
+		// terminate the session.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil
+		}
+
+		return fmt.Errorf("pipe: %w", err)
+	}
+
+	return nil
+}
+
+// onResponse passes the response as stdin to the OnResponse shell command
+// and writes its stdout to w. Errors of the command are written to stderr.
+func onResponse(w io.Writer, ses *config.Session, response string) {
+	cmd := shellCommand(ses.OnResponse)
+	cmd.Stdin = strings.NewReader(response + "\n")
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, fmt.Errorf("on response: %w", err))
+	}
+}
+
+// PagerEnv is the environment variable with the pager command.
+const PagerEnv = "PAGER"
+
+// DefaultPager is the pager command which is used if PagerEnv is not set.
+// It exits immediately if the response fits the terminal.
+const DefaultPager = "less -F -X"
+
+// pagerCommand returns the pager command from PagerEnv or DefaultPager.
+func pagerCommand() string {
+	if pager := os.Getenv(PagerEnv); pager != "" {
+		return pager
+	}
+
+	return DefaultPager
+}
+
+// page passes the printed response as stdin to the Pager shell command and
+// waits for the pager to exit. Errors of the command are written to stderr.
+func (executor *Executor) page(w io.Writer, ses *config.Session, response *Response) {
+	var buf bytes.Buffer
+
+	executor.printResponse(&buf, ses, response)
+
+	cmd := shellCommand(ses.Pager)
+	cmd.Stdin = &buf
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, fmt.Errorf("pager: %w", err))
+	}
+}
+
+// Hook environment variables.
+const (
+	HookCommandEnv  = "RCON_CMD"
+	HookResponseEnv = "RCON_RESPONSE"
+	HookAddressEnv  = "RCON_ADDRESS"
+	HookEnvEnv      = "RCON_ENV"
+)
+
+// runHook runs the local shell command with inherited stdout and stderr. The
+// vars are added to the command environment. Buffered output is flushed
+// first to keep the order of responses and the command output.
+func (executor *Executor) runHook(command string, vars ...string) error {
+	executor.flushOutput()
+
+	cmd := shellCommand(command)
+	cmd.Env = append(os.Environ(), vars...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// shellCommand returns the command to run in the local shell.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+
+	return exec.Command("sh", "-c", command)
+}
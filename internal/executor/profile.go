@@ -0,0 +1,146 @@
+package executor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/path"
+	"github.com/gorcon/rcon-cli/internal/profile"
+	"github.com/urfave/cli/v2"
+)
+
+// configSource returns config file name and environment. Values of the
+// profile are used if --profile flag is set, --config and --env flags
+// override them.
+func configSource(c *cli.Context) (string, string, error) {
+	name, env := c.String("config"), lookupString(c, "env")
+
+	if profileName := c.String("profile"); profileName != "" {
+		profiles, err := loadProfiles()
+		if err != nil {
+			return "", "", err
+		}
+
+		p, err := profiles.Get(profileName)
+		if err != nil {
+			return "", "", fmt.Errorf("profile: %w", err)
+		}
+
+		if !c.IsSet("config") {
+			name = path.ExpandHome(p.Config)
+		}
+
+		if !c.IsSet("env") {
+			env = p.Env
+		}
+	}
+
+	if env == "" {
+		env = config.DefaultConfigEnv
+	}
+
+	return name, env, nil
+}
+
+// loadProfiles reads profiles file from the user home directory.
+func loadProfiles() (profile.Profiles, error) {
+	name, err := profile.DefaultPath()
+	if err != nil {
+		return nil, fmt.Errorf("profile: %w", err)
+	}
+
+	profiles, err := profile.Load(name)
+	if err != nil {
+		return nil, fmt.Errorf("profile: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// saveProfiles writes profiles file to the user home directory.
+func saveProfiles(profiles profile.Profiles) error {
+	name, err := profile.DefaultPath()
+	if err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+
+	if err := profiles.Save(name); err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+
+	return nil
+}
+
+// addProfile adds a new profile pointing to the config file and environment.
+// Config file path is saved as absolute to use the profile from any directory.
+func (executor *Executor) addProfile(c *cli.Context) error {
+	const minArgs, maxArgs = 2, 3
+
+	if c.NArg() < minArgs || c.NArg() > maxArgs {
+		return fmt.Errorf("%w: expected <name> <config> [env]", ErrInvalidArguments)
+	}
+
+	cfg, err := filepath.Abs(c.Args().Get(1))
+	if err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+
+	env := c.Args().Get(2)
+	if env == "" {
+		env = config.DefaultConfigEnv
+	}
+
+	profiles, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+
+	if err := profiles.Add(c.Args().First(), profile.Profile{Config: cfg, Env: env}); err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+
+	if err := saveProfiles(profiles); err != nil {
+		return err
+	}
+
+	profiles.Print(executor.w)
+
+	return nil
+}
+
+// removeProfile removes profile by name.
+func (executor *Executor) removeProfile(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("%w: expected <name>", ErrInvalidArguments)
+	}
+
+	profiles, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+
+	if err := profiles.Remove(c.Args().First()); err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+
+	if err := saveProfiles(profiles); err != nil {
+		return err
+	}
+
+	profiles.Print(executor.w)
+
+	return nil
+}
+
+// listProfiles prints all profiles.
+func (executor *Executor) listProfiles(_ *cli.Context) error {
+	profiles, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+
+	profiles.Print(executor.w)
+
+	return nil
+}
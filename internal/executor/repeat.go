@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// ClearScreen is ANSI escape sequence which clears the terminal and moves
+// the cursor to the top left corner.
+const ClearScreen = "\033[2J\033[H"
+
+// WatchSeparator is written between watch iterations when the terminal is
+// not cleared.
+const WatchSeparator = "========"
+
+// Watch executes commands every Watch interval until an error occurs or
+// WatchCount iterations are done. The terminal is cleared before each
+// iteration if WatchClear is set, otherwise iterations are separated with
+// WatchSeparator line. Config is reloaded before each iteration if
+// --config-watch is set, the connection is reopened with the new address and
+// password.
+func (executor *Executor) Watch(w io.Writer, ses *config.Session, commands []string) error {
+	for i := 0; ses.WatchCount == 0 || i < ses.WatchCount; i++ {
+		if i > 0 {
+			time.Sleep(ses.Watch)
+		}
+
+		if executor.watcher != nil && executor.watcher.reload(os.Stderr, ses) && executor.client != nil {
+			_ = executor.client.Close()
+			executor.client = nil
+		}
+
+		switch {
+		case ses.WatchClear:
+			_, _ = fmt.Fprint(w, ClearScreen)
+		case i > 0:
+			_, _ = fmt.Fprintln(w, WatchSeparator)
+		}
+
+		if err := executor.Execute(w, ses, commands...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,360 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"golang.org/x/term"
+)
+
+// CommandPrompt is printed before reading each command in Interactive mode.
+const CommandPrompt = "> "
+
+// DefaultHistoryFileName is the name of the commands history file in the user
+// home directory.
+const DefaultHistoryFileName = ".rcon_history"
+
+// DefaultHistorySize is the maximum number of commands kept in history file.
+const DefaultHistorySize = 1000
+
+// lineScanner reads input line by line.
+type lineScanner interface {
+	Scan() bool
+	Text() string
+}
+
+// commandScanner reads commands line by line in Interactive mode.
+type commandScanner interface {
+	lineScanner
+	Close() error
+}
+
+// newCommandScanner returns readline scanner if history search is enabled
+// or history file is set and plain line scanner otherwise. Waiting for the
+// next line is interrupted when ctx is done if session time limits are set.
+func newCommandScanner(ctx context.Context, r io.Reader, w io.Writer, ses *config.Session) (commandScanner, error) {
+	limited := ses.SessionMaxDuration > 0 || ses.ExitOnIdle > 0
+
+	if (ses.HistorySearch || ses.HistoryFile != "") && ses.StdinDelimiter == "" {
+		scanner, err := newReadlineScanner(r, w, ses)
+		if err != nil || !limited {
+			return scanner, err
+		}
+
+		return newLimitScanner(ctx, scanner), nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	if ses.StdinDelimiter != "" {
+		scanner.Split(splitDelimiter(ses.StdinDelimiter))
+	}
+
+	var lines lineScanner = scanner
+	if limited {
+		lines = newLimitScanner(ctx, lines)
+	}
+
+	return &promptScanner{lineScanner: lines, w: w, prompt: commandPrompt(ses)}, nil
+}
+
+// ParseStdinDelimiter unescapes delimiter given in Go string literal form.
+// \0 is accepted as the NUL symbol for xargs -0.
+func ParseStdinDelimiter(delimiter string) (string, error) {
+	if delimiter == `\0` {
+		return "\x00", nil
+	}
+
+	unquoted, err := strconv.Unquote(`"` + delimiter + `"`)
+	if err != nil {
+		return "", fmt.Errorf("stdin delimiter: %w", err)
+	}
+
+	return unquoted, nil
+}
+
+// splitDelimiter returns split function which reads commands separated by
+// delimiter instead of lines. Line breaks around commands are removed, so
+// delimiter can be written on its own line between multi-line commands.
+func splitDelimiter(delimiter string) bufio.SplitFunc {
+	separator := []byte(delimiter)
+
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		if i := bytes.Index(data, separator); i >= 0 {
+			return i + len(separator), bytes.Trim(data[:i], "\r\n"), nil
+		}
+
+		if atEOF {
+			return len(data), bytes.Trim(data, "\r\n"), nil
+		}
+
+		return 0, nil, nil
+	}
+}
+
+// commandPrompt returns CommandPrompt or empty string if prompt is disabled.
+func commandPrompt(ses *config.Session) string {
+	if ses.NoPrompt {
+		return ""
+	}
+
+	return CommandPrompt
+}
+
+// isTerminal checks whether r is a terminal. Readers which are not files are
+// considered as terminals to keep prompts in embedded usage.
+func isTerminal(r io.Reader) bool {
+	file, ok := r.(*os.File)
+	if !ok {
+		return true
+	}
+
+	return term.IsTerminal(int(file.Fd()))
+}
+
+// isTerminalOutput checks whether w is a terminal. Unlike isTerminal writers
+// which are not files are not considered as terminals.
+func isTerminalOutput(w io.Writer) bool {
+	file, ok := w.(*os.File)
+
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// promptScanner prints the prompt before reading each line.
+type promptScanner struct {
+	lineScanner
+	w      io.Writer
+	prompt string
+}
+
+// Scan prints the prompt and advances the scanner to the next line.
+func (s *promptScanner) Scan() bool {
+	_, _ = fmt.Fprint(s.w, s.prompt)
+
+	return s.lineScanner.Scan()
+}
+
+// Close stops background reading if lines are read with time limits.
+func (s *promptScanner) Close() error {
+	if scanner, ok := s.lineScanner.(commandScanner); ok {
+		return scanner.Close()
+	}
+
+	return nil
+}
+
+// readlineScanner reads lines with line editing and history. Reverse
+// history search is triggered by Ctrl-R.
+type readlineScanner struct {
+	instance *readline.Instance
+	line     string
+}
+
+// newReadlineScanner creates readline scanner. History is saved to the
+// history file unless NoHistory is set. Ctrl-R is ignored if history search is
+// not enabled.
+func newReadlineScanner(r io.Reader, w io.Writer, ses *config.Session) (*readlineScanner, error) {
+	cfg := readline.Config{
+		Prompt:            commandPrompt(ses),
+		HistorySearchFold: true,
+		HistoryLimit:      ses.HistorySize,
+		AutoComplete:      newCompleter(ses),
+		Stdin:             io.NopCloser(r),
+		Stdout:            w,
+	}
+
+	if cfg.HistoryLimit == 0 {
+		cfg.HistoryLimit = DefaultHistorySize
+	}
+
+	if !ses.NoHistory {
+		name, err := historyFile(ses.HistoryFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.HistoryFile = name
+	}
+
+	if !ses.HistorySearch {
+		cfg.FuncFilterInputRune = func(r rune) (rune, bool) {
+			return r, r != readline.CharBckSearch
+		}
+	}
+
+	instance, err := readline.NewEx(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("readline: %w", err)
+	}
+
+	return &readlineScanner{instance: instance}, nil
+}
+
+// newCompleter returns Tab completer of terminal mode commands and aliases.
+func newCompleter(ses *config.Session) readline.AutoCompleter {
+	names := make([]string, 0, len(ses.Aliases))
+	for name := range ses.Aliases {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	items := []readline.PrefixCompleterInterface{readline.PcItem(CommandQuit), readline.PcItem(CommandAliases),
+		readline.PcItem(CommandPaste), readline.PcItem(CommandNoPaste), readline.PcItem(CommandPasteEnd)}
+	for _, name := range names {
+		items = append(items, readline.PcItem(name))
+	}
+
+	return readline.NewPrefixCompleter(items...)
+}
+
+// historyFile returns path to history file and creates its directory. Path to
+// DefaultHistoryFileName in the user home directory is returned if name is
+// empty.
+func historyFile(name string) (string, error) {
+	if name == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("history file: %w", err)
+		}
+
+		return filepath.Join(home, DefaultHistoryFileName), nil
+	}
+
+	const perm = 0o755
+
+	if err := os.MkdirAll(filepath.Dir(name), perm); err != nil {
+		return "", fmt.Errorf("history file: %w", err)
+	}
+
+	return name, nil
+}
+
+// Scan reads the next line. Returns false on EOF and interrupt.
+func (s *readlineScanner) Scan() bool {
+	line, err := s.instance.Readline()
+	if err != nil {
+		return false
+	}
+
+	s.line = line
+
+	return true
+}
+
+// Text returns the most recent line read by Scan.
+func (s *readlineScanner) Text() string {
+	return s.line
+}
+
+// Close restores the terminal state.
+func (s *readlineScanner) Close() error {
+	return s.instance.Close()
+}
+
+// scanCommand waits for the next command. Idle timer runs only while waiting
+// so that it does not fire while a command is being executed.
+func scanCommand(scanner commandScanner, idle *time.Timer, timeout time.Duration) bool {
+	if idle == nil {
+		return scanner.Scan()
+	}
+
+	idle.Reset(timeout)
+	defer idle.Stop()
+
+	return scanner.Scan()
+}
+
+// limitScanner reads lines of the underlying scanner in background so that
+// waiting for the next command can be interrupted when ctx is done.
+type limitScanner struct {
+	scanner lineScanner
+	ctx     context.Context
+	next    chan struct{}
+	lines   chan string
+	line    string
+	done    bool
+}
+
+// newLimitScanner starts reading lines of scanner until ctx is done.
+func newLimitScanner(ctx context.Context, scanner lineScanner) *limitScanner {
+	s := &limitScanner{
+		scanner: scanner,
+		ctx:     ctx,
+		next:    make(chan struct{}),
+		lines:   make(chan string, 1),
+	}
+
+	go s.run()
+
+	return s
+}
+
+// run scans the next line of the underlying scanner on each request.
+func (s *limitScanner) run() {
+	defer close(s.lines)
+
+	for range s.next {
+		if !s.scanner.Scan() {
+			return
+		}
+
+		s.lines <- s.scanner.Text()
+	}
+}
+
+// Scan waits for the next line. Returns false on EOF and when ctx is done.
+func (s *limitScanner) Scan() bool {
+	if s.done || s.ctx.Err() != nil {
+		return false
+	}
+
+	s.next <- struct{}{}
+
+	select {
+	case line, ok := <-s.lines:
+		if !ok {
+			s.done = true
+
+			return false
+		}
+
+		s.line = line
+
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// Text returns the most recent line read by Scan.
+func (s *limitScanner) Text() string {
+	return s.line
+}
+
+// Close stops background reading and closes the underlying scanner if it is
+// commandScanner.
+func (s *limitScanner) Close() error {
+	if !s.done {
+		close(s.next)
+	}
+
+	if scanner, ok := s.scanner.(commandScanner); ok {
+		return scanner.Close()
+	}
+
+	return nil
+}
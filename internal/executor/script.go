@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/script"
+)
+
+// RunScript runs the Tengo script file. Commands from rcon.execute function
+// are sent to the session server, printed values are written to w. The script
+// is stopped after timeout if it is positive.
+func (executor *Executor) RunScript(w io.Writer, ses *config.Session, name string, timeout time.Duration) error {
+	source, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("script: %w", err)
+	}
+
+	if err = script.Run(w, &scriptExecutor{executor: executor, ses: ses}, source, timeout); err != nil {
+		return fmt.Errorf("script: %w", err)
+	}
+
+	return nil
+}
+
+// scriptExecutor executes commands of scripts in the session. Responses are
+// processed as printed ones, so formatting and masking flags are applied.
+type scriptExecutor struct {
+	executor *Executor
+	ses      *config.Session
+}
+
+// Execute executes the command and returns the response without the
+// trailing terminator.
+func (e *scriptExecutor) Execute(command string) (string, error) {
+	var response bytes.Buffer
+
+	err := e.executor.Execute(&response, e.ses, command)
+
+	return strings.TrimSuffix(response.String(), responseTerminator(e.ses)), err
+}
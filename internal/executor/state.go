@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// SessionStateVersion is the current version of the session file format.
+// Files without version are treated as version 1. Unknown fields are
+// ignored, so new fields can be added without changing the version.
+const SessionStateVersion = 1
+
+// SessionState contains the state of terminal mode saved between runs.
+// Aliases are not saved because they are defined in the config only, so
+// --no-config-aliases and aliases removed from the config are respected.
+type SessionState struct {
+	Version int    `json:"version"`
+	Address string `json:"address,omitempty"`
+	// HistoryPosition is the total number of commands executed in the
+	// saved sessions.
+	HistoryPosition int `json:"history_position"`
+}
+
+// ReadSessionState reads the session state from the file. Empty state is
+// returned if file does not exist.
+func ReadSessionState(name string) (*SessionState, error) {
+	state := SessionState{Version: SessionStateVersion}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &state, nil
+		}
+
+		return nil, fmt.Errorf("session file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("session file: %w", err)
+	}
+
+	if state.Version == 0 {
+		state.Version = SessionStateVersion
+	}
+
+	if state.Version > SessionStateVersion {
+		return nil, fmt.Errorf("%w: %d", ErrSessionFileVersion, state.Version)
+	}
+
+	return &state, nil
+}
+
+// WriteSessionState saves the session state to the file.
+func WriteSessionState(name string, state *SessionState) error {
+	const perm = 0o600
+
+	state.Version = SessionStateVersion
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("session file: %w", err)
+	}
+
+	if err := os.WriteFile(name, append(data, '\n'), perm); err != nil {
+		return fmt.Errorf("session file: %w", err)
+	}
+
+	return nil
+}
+
+// restore fills the session address if it is not set.
+func (state *SessionState) restore(ses *config.Session) {
+	if ses.Address == "" {
+		ses.Address = state.Address
+	}
+}
+
+// update saves the session address to the state.
+func (state *SessionState) update(ses *config.Session, executed int) {
+	state.Address = ses.Address
+	state.HistoryPosition += executed
+}
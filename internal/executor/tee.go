@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gorcon/rcon-cli/internal/ansi"
+	"github.com/gorcon/rcon-cli/internal/logger"
+)
+
+// teeWriter writes a copy of everything written to the underlying writer
+// to the file. Errors of the file writing are ignored so as not to break
+// the session.
+type teeWriter struct {
+	w    io.Writer
+	file io.Writer
+}
+
+// newTeeWriter creates a new teeWriter.
+func newTeeWriter(w io.Writer, file io.Writer) *teeWriter {
+	return &teeWriter{w: w, file: file}
+}
+
+// Write writes p to the underlying writer and to the file.
+func (t *teeWriter) Write(p []byte) (int, error) {
+	n, err := t.w.Write(p)
+
+	_, _ = t.file.Write(p)
+
+	return n, err
+}
+
+// tee wraps executor writer with teeWriter which mirrors the output to the
+// file. ANSI escape sequences are stripped from the file branch if noColor
+// is true. Returned function closes the file and restores the writer.
+func (executor *Executor) tee(name string, noColor bool) (func(), error) {
+	file, err := logger.OpenFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("tee: %w", err)
+	}
+
+	var fileWriter io.Writer = file
+	if noColor {
+		fileWriter = ansi.NewWriter(file)
+	}
+
+	w := executor.w
+	executor.w = newTeeWriter(w, fileWriter)
+
+	return func() {
+		executor.w = w
+		_ = file.Close()
+	}, nil
+}
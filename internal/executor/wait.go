@@ -0,0 +1,42 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// WaitForServerInterval is the delay between connection attempts while
+// waiting for the server.
+const WaitForServerInterval = 2 * time.Second
+
+// WaitForServer checks credentials every WaitForServerInterval until the
+// server accepts them or timeout expires. A dot is written to w after each
+// failed attempt. Authentication failure is returned immediately because
+// the server is already reachable.
+func (executor *Executor) WaitForServer(w io.Writer, ses *config.Session, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for attempts := 0; ; attempts++ {
+		err := executor.CheckCredentials(ses)
+		if err == nil || isAuthFailed(err) {
+			if attempts > 0 {
+				_, _ = fmt.Fprintln(w)
+			}
+
+			return err
+		}
+
+		_, _ = fmt.Fprint(w, ".")
+
+		if time.Now().Add(WaitForServerInterval).After(deadline) {
+			_, _ = fmt.Fprintln(w)
+
+			return fmt.Errorf("%w in %s: %w", ErrTimeout, timeout, err)
+		}
+
+		time.Sleep(WaitForServerInterval)
+	}
+}
@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// configWatcher reloads config environment in terminal and watch modes when
+// the config file is modified. Write events are received by fsnotify in the
+// background, the session is updated by reload before the next command, so
+// it is not modified concurrently with executed commands.
+type configWatcher struct {
+	name    string
+	env     string
+	events  *fsnotify.Watcher
+	changed atomic.Bool
+
+	// template is true if the config file is rendered as template.
+	template bool
+
+	// address, password and aliases are true if the values are taken from
+	// the config environment and not from flags.
+	address  bool
+	password bool
+	aliases  bool
+}
+
+// newConfigWatcher creates a new configWatcher. Nil is returned if the config
+// file is not accessible, e.g. when config is passed in JSONEnvVariable, or
+// can not be watched.
+func newConfigWatcher(name string, env string) *configWatcher {
+	if _, err := os.Stat(name); err != nil || os.Getenv(config.JSONEnvVariable) != "" {
+		return nil
+	}
+
+	events, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil
+	}
+
+	// Directory is watched because editors replace the file by renaming.
+	if err = events.Add(filepath.Dir(name)); err != nil {
+		_ = events.Close()
+
+		return nil
+	}
+
+	watcher := &configWatcher{name: filepath.Clean(name), env: env, events: events}
+
+	go watcher.watch()
+
+	return watcher
+}
+
+// watch marks the config as changed on write and create events of the config
+// file until the watcher is closed.
+func (watcher *configWatcher) watch() {
+	for {
+		select {
+		case event, ok := <-watcher.events.Events:
+			if !ok {
+				return
+			}
+
+			if filepath.Clean(event.Name) == watcher.name && event.Has(fsnotify.Write|fsnotify.Create) {
+				watcher.changed.Store(true)
+			}
+		case _, ok := <-watcher.events.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// close stops watching the config file.
+func (watcher *configWatcher) close() {
+	_ = watcher.events.Close()
+}
+
+// reload updates session fields taken from config environment if the config
+// file is modified and prints "Config reloaded" to w. The previous values are
+// kept and the error is printed if the file can not be parsed. It returns true
+// if address or password are changed.
+func (watcher *configWatcher) reload(w io.Writer, ses *config.Session) bool {
+	if !watcher.changed.Swap(false) {
+		return false
+	}
+
+	cfg, err := config.NewConfig(watcher.name, config.RenderTemplate(watcher.template))
+	if err != nil {
+		_, _ = fmt.Fprintln(w, fmt.Errorf("config reload: %w", err))
+
+		return false
+	}
+
+	env, ok := (*cfg)[watcher.env]
+	if !ok {
+		_, _ = fmt.Fprintln(w, fmt.Errorf("config reload: %w: %s", config.ErrEnvNotFound, watcher.env))
+
+		return false
+	}
+
+	address, password := ses.Address, ses.Password
+
+	if watcher.address {
+		ses.Address = env.Address
+	}
+
+	if watcher.password {
+		ses.Password = env.Password
+	}
+
+	if watcher.aliases {
+		ses.Aliases = env.Aliases
+	}
+
+	_, _ = fmt.Fprintln(w, "Config reloaded")
+
+	return ses.Address != address || ses.Password != password
+}
@@ -0,0 +1,64 @@
+package format
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Styles of formatted durations.
+const (
+	DurationStyleClock = "clock"
+	DurationStyleHuman = "human"
+)
+
+// kvLine matches "key: value" and "key=value" response lines with integer
+// values.
+var kvLine = regexp.MustCompile(`^(\s*)([^\s:=]+)(\s*[:=]\s*)(\d+)(\s*)$`)
+
+// Durations parses response lines as key-value pairs and reformats values of
+// the keys, given in seconds, as HH:MM:SS in clock style or as Go duration,
+// e.g. 24h0m0s, in human style. Other lines are not changed.
+func Durations(response string, keys []string, style string) string {
+	if len(keys) == 0 {
+		return response
+	}
+
+	lines := strings.Split(response, "\n")
+
+	for i, line := range lines {
+		match := kvLine.FindStringSubmatch(line)
+		if match == nil || !containsKey(keys, match[2]) {
+			continue
+		}
+
+		seconds, err := strconv.ParseInt(match[4], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		lines[i] = match[1] + match[2] + match[3] + formatDuration(seconds, style) + match[5]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatDuration(seconds int64, style string) string {
+	if style == DurationStyleHuman {
+		return (time.Duration(seconds) * time.Second).String()
+	}
+
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
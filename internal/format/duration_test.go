@@ -0,0 +1,27 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurations(t *testing.T) {
+	response := "uptime: 86400\nplayers: 12\nplayTime=3725\nname: uptime 5"
+	keys := []string{"uptime", "PLAYTIME"}
+
+	t.Run("clock", func(t *testing.T) {
+		result := format.Durations(response, keys, format.DurationStyleClock)
+		assert.Equal(t, "uptime: 24:00:00\nplayers: 12\nplayTime=01:02:05\nname: uptime 5", result)
+	})
+
+	t.Run("human", func(t *testing.T) {
+		result := format.Durations(response, keys, format.DurationStyleHuman)
+		assert.Equal(t, "uptime: 24h0m0s\nplayers: 12\nplayTime=1h2m5s\nname: uptime 5", result)
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		assert.Equal(t, response, format.Durations(response, nil, format.DurationStyleClock))
+	})
+}
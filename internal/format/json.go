@@ -0,0 +1,95 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// ANSI colors of JSON tokens.
+const (
+	ColorKey     = "\x1b[34;1m"
+	ColorString  = "\x1b[32m"
+	ColorNumber  = "\x1b[36m"
+	ColorLiteral = "\x1b[35m"
+	ColorReset   = "\x1b[0m"
+)
+
+// JSON indents valid JSON response by indent spaces and highlights tokens with
+// ANSI colors if color is true. Keys order and numbers are kept as is. Response
+// which is not valid JSON is returned unchanged.
+func JSON(response string, indent int, color bool) string {
+	if (indent <= 0 && !color) || !json.Valid([]byte(response)) {
+		return response
+	}
+
+	if indent > 0 {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(response), "", strings.Repeat(" ", indent)); err == nil {
+			response = buf.String()
+		}
+	}
+
+	if color {
+		response = highlight(response)
+	}
+
+	return response
+}
+
+// highlight wraps keys, strings, numbers and literals of valid JSON in ANSI
+// colors. Punctuation and whitespaces are not changed.
+func highlight(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '"':
+			end := stringEnd(s, i)
+
+			color := ColorString
+			if next := strings.TrimLeft(s[end:], " \t\r\n"); strings.HasPrefix(next, ":") {
+				color = ColorKey
+			}
+
+			b.WriteString(color + s[i:end] + ColorReset)
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(s) && strings.IndexByte("0123456789.eE+-", s[end]) >= 0 {
+				end++
+			}
+
+			b.WriteString(ColorNumber + s[i:end] + ColorReset)
+			i = end
+		case c == 't' || c == 'f' || c == 'n':
+			end := i + 1
+			for end < len(s) && s[end] >= 'a' && s[end] <= 'z' {
+				end++
+			}
+
+			b.WriteString(ColorLiteral + s[i:end] + ColorReset)
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// stringEnd returns the position after the closing quote of JSON string which
+// starts at start.
+func stringEnd(s string, start int) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return len(s)
+}
@@ -0,0 +1,29 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON(t *testing.T) {
+	response := `{"name":"a \"b\"","players":[1,-2.5e3],"ok":true,"map":null}`
+
+	t.Run("indent", func(t *testing.T) {
+		result := format.JSON(response, 2, false)
+		assert.Equal(t, "{\n  \"name\": \"a \\\"b\\\"\",\n  \"players\": [\n    1,\n    -2.5e3\n  ],\n  \"ok\": true,\n  \"map\": null\n}", result)
+	})
+
+	t.Run("color", func(t *testing.T) {
+		result := format.JSON(`{"name":"admin","ping":25,"ok":false}`, 0, true)
+		assert.Equal(t, "{"+format.ColorKey+`"name"`+format.ColorReset+":"+format.ColorString+`"admin"`+format.ColorReset+
+			","+format.ColorKey+`"ping"`+format.ColorReset+":"+format.ColorNumber+"25"+format.ColorReset+
+			","+format.ColorKey+`"ok"`+format.ColorReset+":"+format.ColorLiteral+"false"+format.ColorReset+"}", result)
+	})
+
+	t.Run("not json", func(t *testing.T) {
+		assert.Equal(t, "Players connected (0):", format.JSON("Players connected (0):", 2, true))
+		assert.Equal(t, response, format.JSON(response, 0, false))
+	})
+}
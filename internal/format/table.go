@@ -0,0 +1,48 @@
+// Package format contains formatters of server responses.
+package format
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+)
+
+// TablePadding is the number of spaces between table columns.
+const TablePadding = 2
+
+// Table splits each line of response by sep and renders lines as a table with
+// aligned columns. If header is true, the first line is separated from the
+// rows by a line of dashes. Empty lines are skipped.
+func Table(response string, sep *regexp.Regexp, header bool) string {
+	var buf bytes.Buffer
+
+	w := tabwriter.NewWriter(&buf, 0, 0, TablePadding, ' ', 0)
+
+	first := true
+
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		cells := sep.Split(line, -1)
+		_, _ = w.Write([]byte(strings.Join(cells, "\t") + "\n"))
+
+		if first && header {
+			dashes := make([]string, len(cells))
+			for i, cell := range cells {
+				dashes[i] = strings.Repeat("-", len([]rune(cell)))
+			}
+
+			_, _ = w.Write([]byte(strings.Join(dashes, "\t") + "\n"))
+		}
+
+		first = false
+	}
+
+	_ = w.Flush()
+
+	return strings.TrimRight(buf.String(), "\n")
+}
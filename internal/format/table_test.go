@@ -0,0 +1,28 @@
+package format_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTable(t *testing.T) {
+	response := "id,name,ping\n1,admin,25\n\n12,player,130\n"
+
+	t.Run("no header", func(t *testing.T) {
+		result := format.Table(response, regexp.MustCompile(`,`), false)
+		assert.Equal(t, "id  name    ping\n1   admin   25\n12  player  130", result)
+	})
+
+	t.Run("header", func(t *testing.T) {
+		result := format.Table(response, regexp.MustCompile(`,`), true)
+		assert.Equal(t, "id  name    ping\n--  ----    ----\n1   admin   25\n12  player  130", result)
+	})
+
+	t.Run("whitespace separator", func(t *testing.T) {
+		result := format.Table("a b\tc\nlong   value x", regexp.MustCompile(`\s+`), false)
+		assert.Equal(t, "a     b      c\nlong  value  x", result)
+	})
+}
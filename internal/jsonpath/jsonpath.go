@@ -0,0 +1,212 @@
+// Package jsonpath implements a minimal JSONPath evaluator. It supports the
+// root $, child .name and ['name'], index [n], and wildcard .* and [*]
+// selectors.
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPath is returned when JSONPath expression can not be parsed.
+var ErrInvalidPath = errors.New("invalid json path")
+
+// step is a single selector of the path.
+type step struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// Path is a compiled JSONPath expression.
+type Path struct {
+	expr  string
+	steps []step
+}
+
+// Compile parses JSONPath expression.
+func Compile(expr string) (*Path, error) {
+	rest, ok := strings.CutPrefix(expr, "$")
+	if !ok {
+		return nil, fmt.Errorf("%w %q: must start with $", ErrInvalidPath, expr)
+	}
+
+	path := Path{expr: expr}
+
+	for rest != "" {
+		var (
+			s   step
+			err error
+		)
+
+		switch rest[0] {
+		case '.':
+			s, rest, err = parseDot(rest[1:])
+		case '[':
+			s, rest, err = parseBracket(rest[1:])
+		default:
+			err = fmt.Errorf("unexpected %q", rest[0])
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %s", ErrInvalidPath, expr, err)
+		}
+
+		path.steps = append(path.steps, s)
+	}
+
+	return &path, nil
+}
+
+// String returns the source expression.
+func (path *Path) String() string {
+	return path.expr
+}
+
+// Find returns values of data matched by the path. Data is a value decoded
+// by encoding/json. Wildcard matches object members in key order.
+func (path *Path) Find(data any) []any {
+	values := []any{data}
+
+	for _, s := range path.steps {
+		var next []any
+
+		for _, value := range values {
+			next = append(next, s.apply(value)...)
+		}
+
+		values = next
+	}
+
+	return values
+}
+
+// Extract decodes JSON data and returns matched values joined with newlines.
+// Strings are returned as is, other values are encoded to JSON.
+func (path *Path) Extract(data string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("decode: %w", err)
+	}
+
+	lines := make([]string, 0)
+
+	for _, match := range path.Find(value) {
+		if str, ok := match.(string); ok {
+			lines = append(lines, str)
+
+			continue
+		}
+
+		var buf bytes.Buffer
+
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+
+		if err := encoder.Encode(match); err != nil {
+			return "", fmt.Errorf("encode: %w", err)
+		}
+
+		lines = append(lines, strings.TrimSuffix(buf.String(), "\n"))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// apply returns children of value selected by step.
+func (s step) apply(value any) []any {
+	switch v := value.(type) {
+	case map[string]any:
+		if s.wildcard {
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			values := make([]any, 0, len(keys))
+			for _, key := range keys {
+				values = append(values, v[key])
+			}
+
+			return values
+		}
+
+		if child, ok := v[s.key]; ok && !s.isIndex {
+			return []any{child}
+		}
+	case []any:
+		if s.wildcard {
+			return v
+		}
+
+		index := s.index
+		if index < 0 {
+			index += len(v)
+		}
+
+		if s.isIndex && index >= 0 && index < len(v) {
+			return []any{v[index]}
+		}
+	}
+
+	return nil
+}
+
+// parseDot parses .name and .* selectors without the leading dot.
+func parseDot(rest string) (step, string, error) {
+	end := strings.IndexAny(rest, ".[")
+	if end == -1 {
+		end = len(rest)
+	}
+
+	name := rest[:end]
+
+	switch name {
+	case "":
+		return step{}, "", errors.New("empty member name")
+	case "*":
+		return step{wildcard: true}, rest[end:], nil
+	default:
+		return step{key: name}, rest[end:], nil
+	}
+}
+
+// parseBracket parses [n], [*] and ['name'] selectors without the leading
+// bracket.
+func parseBracket(rest string) (step, string, error) {
+	if rest != "" && (rest[0] == '\'' || rest[0] == '"') {
+		end := strings.IndexByte(rest[1:], rest[0])
+		if end == -1 || !strings.HasPrefix(rest[end+2:], "]") {
+			return step{}, "", errors.New("unterminated member name")
+		}
+
+		return step{key: rest[1 : end+1]}, rest[end+3:], nil
+	}
+
+	end := strings.IndexByte(rest, ']')
+	if end == -1 {
+		return step{}, "", errors.New("unterminated bracket")
+	}
+
+	if rest[:end] == "*" {
+		return step{wildcard: true}, rest[end+1:], nil
+	}
+
+	index, err := strconv.Atoi(rest[:end])
+	if err != nil {
+		return step{}, "", fmt.Errorf("invalid index %q", rest[:end])
+	}
+
+	return step{index: index, isIndex: true}, rest[end+1:], nil
+}
@@ -0,0 +1,48 @@
+package jsonpath_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/jsonpath"
+	"github.com/stretchr/testify/assert"
+)
+
+const response = `{"count": 2, "players": [{"name": "admin", "id": 1}, {"name": "player", "id": 2}],` +
+	` "server": {"map name": "Muldraugh", "version": "41.78"}}`
+
+func TestCompile(t *testing.T) {
+	for _, expr := range []string{"players", "$.", "$[0", "$['name]", "$[x]", "$!"} {
+		_, err := jsonpath.Compile(expr)
+		assert.ErrorIs(t, err, jsonpath.ErrInvalidPath, expr)
+	}
+}
+
+func TestPath_Extract(t *testing.T) {
+	for expr, expected := range map[string]string{
+		"$":                      `{"count":2,"players":[{"id":1,"name":"admin"},{"id":2,"name":"player"}],"server":{"map name":"Muldraugh","version":"41.78"}}`,
+		"$.count":                "2",
+		"$.players[*].name":      "admin\nplayer",
+		"$.players[-1].id":       "2",
+		"$['players'][0]":        `{"id":1,"name":"admin"}`,
+		"$.server[\"map name\"]": "Muldraugh",
+		"$.server.*":             "Muldraugh\n41.78",
+		"$.unknown":              "",
+		"$.players.name":         "",
+		"$.players[5]":           "",
+	} {
+		path, err := jsonpath.Compile(expr)
+		if !assert.NoError(t, err, expr) {
+			continue
+		}
+
+		result, err := path.Extract(response)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expected, result, expr)
+	}
+
+	path, err := jsonpath.Compile("$.count")
+	assert.NoError(t, err)
+
+	_, err = path.Extract("Players connected (2)")
+	assert.Error(t, err)
+}
@@ -1,21 +1,57 @@
 package logger
 
 import (
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // DefaultTimeLayout is layout for convert time.Now to String.
-const DefaultTimeLayout = "2006-01-02 15:04:05"
+const DefaultTimeLayout = time.RFC3339
+
+// Time formats which are not Go layouts. TimeFormatUnix writes seconds and
+// TimeFormatUnixMilli writes milliseconds since Unix epoch.
+const (
+	TimeFormatUnix      = "unix"
+	TimeFormatUnixMilli = "unixmilli"
+)
 
 // DefaultLineFormat is format to log line record.
 const DefaultLineFormat = "[%s] %s: %s\n%s\n\n"
 
-// ErrEmptyFileName is returned when trying to open file with empty name.
-var ErrEmptyFileName = errors.New("empty file name")
+// LatencyFormat is format of the request with command latency in
+// milliseconds.
+const LatencyFormat = "%s (latency %dms)"
+
+// DefaultHeaderFormat is format of the header line of new log files.
+const DefaultHeaderFormat = "# rcon-cli log started at %s address=%s protocol=%s\n\n"
+
+// GzipExt is the extension which is added to compressed log file name.
+const GzipExt = ".gz"
+
+// SyslogTag is the default tag of syslog messages.
+const SyslogTag = "rcon-cli"
+
+// SyslogTagEnvPlaceholder is replaced with the config environment name in
+// syslog tag.
+const SyslogTagEnvPlaceholder = "%e"
+
+// DefaultAuditLineFormat is format to log line record with operator name.
+const DefaultAuditLineFormat = "[%s] %s@%s: %s\n%s\n\n"
+
+var (
+	// ErrEmptyFileName is returned when trying to open file with empty name.
+	ErrEmptyFileName = errors.New("empty file name")
+
+	// ErrSyslogUnsupported is returned when syslog facility or severity is
+	// unknown or syslog is not available on the platform.
+	ErrSyslogUnsupported = errors.New("unsupported syslog")
+)
 
 // OpenFile opens file for append strings. Creates file if file not exist.
 func OpenFile(name string) (*os.File, error) {
@@ -52,23 +88,170 @@ func OpenFile(name string) (*os.File, error) {
 	return file, nil
 }
 
+// ExpandSyslogTag replaces SyslogTagEnvPlaceholder in tag with env.
+func ExpandSyslogTag(tag string, env string) string {
+	return strings.ReplaceAll(tag, SyslogTagEnvPlaceholder, env)
+}
+
+// Entry contains details of executed request to log.
+type Entry struct {
+	Address  string
+	Request  string
+	Response string
+	// Operator is the name of the user who sent the request. It is written
+	// to the log record if not empty.
+	Operator string
+	// TimeFormat is Go time layout, TimeFormatUnix or TimeFormatUnixMilli
+	// of the record time in text and JSON records. DefaultTimeLayout is used
+	// if empty.
+	TimeFormat string
+	// Latency is the command round-trip time. It is written in milliseconds
+	// to the log record if not zero.
+	Latency time.Duration
+}
+
+// Line returns formatted log record.
+func (entry *Entry) Line(now time.Time) string {
+	if entry.Operator != "" {
+		return fmt.Sprintf(DefaultAuditLineFormat, entry.time(now),
+			entry.Operator, entry.Address, entry.request(), entry.Response)
+	}
+
+	return fmt.Sprintf(DefaultLineFormat, entry.time(now), entry.Address, entry.request(), entry.Response)
+}
+
+// request returns the request of text record with Latency suffix.
+func (entry *Entry) request() string {
+	if entry.Latency == 0 {
+		return entry.Request
+	}
+
+	return fmt.Sprintf(LatencyFormat, entry.Request, entry.Latency.Milliseconds())
+}
+
+// time returns the record time in TimeFormat.
+func (entry *Entry) time(now time.Time) string {
+	switch entry.TimeFormat {
+	case "":
+		return now.Format(DefaultTimeLayout)
+	case TimeFormatUnix:
+		return strconv.FormatInt(now.Unix(), 10)
+	case TimeFormatUnixMilli:
+		return strconv.FormatInt(now.UnixMilli(), 10)
+	default:
+		return now.Format(entry.TimeFormat)
+	}
+}
+
+// Settings contains options to Write.
+type Settings struct {
+	// Header is written before the first entry when the log file is
+	// created.
+	Header string
+	// Sync flushes the log file to disk after every write.
+	Sync bool
+}
+
+// Option allows to inject settings to Settings.
+type Option func(s *Settings)
+
+// SetHeader injects header line of new log files to Settings.
+func SetHeader(header string) Option {
+	return func(s *Settings) {
+		s.Header = header
+	}
+}
+
+// SetSync injects flushing of the log file after every write to Settings.
+func SetSync(sync bool) Option {
+	return func(s *Settings) {
+		s.Sync = sync
+	}
+}
+
+// Header returns header line of the log file. It ends with an empty line so
+// that it is not a part of the first entry.
+func Header(now time.Time, address string, protocol string) string {
+	return fmt.Sprintf(DefaultHeaderFormat, now.UTC().Format(time.RFC3339), address, protocol)
+}
+
 // Write saves request and response to log file.
-func Write(name string, address string, request string, response string) error {
+func Write(name string, entry Entry, options ...Option) error {
 	// Disable logging if log file name is empty.
 	if name == "" {
 		return nil
 	}
 
-	file, err := OpenFile(name)
+	file, settings, err := openLog(name, options)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	line := fmt.Sprintf(DefaultLineFormat, time.Now().Format(DefaultTimeLayout), address, request, response)
-	if _, err = file.WriteString(line); err != nil {
+	if _, err = file.WriteString(settings.Header + entry.Line(time.Now())); err != nil {
 		return fmt.Errorf("write: %w", err)
 	}
 
+	return syncLog(file, settings)
+}
+
+// WriteGzip saves request and response to gzip compressed log file with
+// GzipExt added to name. Each entry is appended as a separate gzip member,
+// standard tools like zcat read such files as a single stream.
+func WriteGzip(name string, entry Entry, options ...Option) error {
+	// Disable logging if log file name is empty.
+	if name == "" {
+		return nil
+	}
+
+	file, settings, err := openLog(name+GzipExt, options)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := gzip.NewWriter(file)
+
+	if _, err = w.Write([]byte(settings.Header + entry.Line(time.Now()))); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return syncLog(file, settings)
+}
+
+// openLog opens log file and returns the settings. Header which must be
+// written before the entry is empty if file already exists.
+func openLog(name string, options []Option) (*os.File, Settings, error) {
+	settings := Settings{}
+	for _, option := range options {
+		option(&settings)
+	}
+
+	if _, err := os.Stat(name); err == nil {
+		settings.Header = ""
+	}
+
+	file, err := OpenFile(name)
+	if err != nil {
+		return nil, settings, err
+	}
+
+	return file, settings, nil
+}
+
+// syncLog flushes written entry to disk if Sync is set.
+func syncLog(file *os.File, settings Settings) error {
+	if !settings.Sync {
+		return nil
+	}
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+
 	return nil
 }
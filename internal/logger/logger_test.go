@@ -1,8 +1,14 @@
 package logger_test
 
 import (
+	"compress/gzip"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorcon/rcon-cli/internal/logger"
 	"github.com/stretchr/testify/assert"
@@ -44,19 +50,194 @@ func TestWrite(t *testing.T) {
 
 	// Test skip log. No logs is available.
 	t.Run("skip log", func(t *testing.T) {
-		err := logger.Write("", address, command, result)
+		err := logger.Write("", logger.Entry{Address: address, Request: command, Response: result})
 		assert.NoError(t, err)
 	})
 
 	// Test create log file.
 	t.Run("create log file", func(t *testing.T) {
-		err := logger.Write(logName, address, command, result)
+		err := logger.Write(logName, logger.Entry{Address: address, Request: command, Response: result})
 		assert.NoError(t, err)
 	})
 
 	// Test append to log file.
 	t.Run("append to log file", func(t *testing.T) {
-		err := logger.Write(logName, address, command, result)
+		err := logger.Write(logName, logger.Entry{Address: address, Request: command, Response: result})
 		assert.NoError(t, err)
 	})
 }
+
+func TestWrite_header(t *testing.T) {
+	logName := filepath.Join(t.TempDir(), "tmpfile.log")
+
+	entry := logger.Entry{Address: "127.0.0.1:16200", Request: "players", Response: "Players connected (0):"}
+	header := logger.Header(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), "127.0.0.1:16200", "rcon")
+	assert.Equal(t, "# rcon-cli log started at 2024-01-15T10:30:00Z address=127.0.0.1:16200 protocol=rcon\n\n", header)
+
+	// Test header is written only to the new file.
+	assert.NoError(t, logger.Write(logName, entry, logger.SetHeader(header)))
+	assert.NoError(t, logger.Write(logName, entry, logger.SetHeader(header)))
+
+	data, err := os.ReadFile(logName)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), header))
+	assert.Equal(t, 1, strings.Count(string(data), "# rcon-cli log started"))
+
+	// Test header is not a part of log entries.
+	entries, err := logger.Tail(logName, 5)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestWrite_sync(t *testing.T) {
+	logName := filepath.Join(t.TempDir(), "tmpfile.log")
+
+	entry := logger.Entry{Address: "127.0.0.1:16200", Request: "players", Response: "Players connected (0):"}
+
+	assert.NoError(t, logger.Write(logName, entry, logger.SetSync(true)))
+	assert.NoError(t, logger.WriteGzip(logName, entry, logger.SetSync(true)))
+
+	data, err := os.ReadFile(logName)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "players")
+	assert.FileExists(t, logName+logger.GzipExt)
+}
+
+func TestWriteGzip(t *testing.T) {
+	logName := filepath.Join(t.TempDir(), "tmpfile.log")
+
+	entry := logger.Entry{Address: "127.0.0.1:16200", Request: "players", Response: "Players connected (0):"}
+
+	// Test skip log. No logs is available.
+	t.Run("skip log", func(t *testing.T) {
+		assert.NoError(t, logger.WriteGzip("", entry))
+	})
+
+	// Test appended entries are read as a single stream.
+	t.Run("append to log file", func(t *testing.T) {
+		assert.NoError(t, logger.WriteGzip(logName, entry))
+		assert.NoError(t, logger.WriteGzip(logName, entry))
+
+		file, err := os.Open(logName + logger.GzipExt)
+		assert.NoError(t, err)
+
+		defer file.Close()
+
+		r, err := gzip.NewReader(file)
+		assert.NoError(t, err)
+
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(data), "127.0.0.1:16200: players\nPlayers connected (0):\n\n"))
+	})
+}
+
+func TestEntry_Line(t *testing.T) {
+	now := time.Date(2023, 3, 11, 12, 0, 0, 0, time.UTC)
+
+	// Test log record without operator.
+	t.Run("no operator", func(t *testing.T) {
+		entry := logger.Entry{Address: "127.0.0.1:16200", Request: "players", Response: "-admin"}
+		assert.Equal(t, "[2023-03-11T12:00:00Z] 127.0.0.1:16200: players\n-admin\n\n", entry.Line(now))
+	})
+
+	// Test audit log record with operator.
+	t.Run("with operator", func(t *testing.T) {
+		entry := logger.Entry{Address: "127.0.0.1:16200", Request: "players", Response: "-admin", Operator: "outdead"}
+		assert.Equal(t, "[2023-03-11T12:00:00Z] outdead@127.0.0.1:16200: players\n-admin\n\n", entry.Line(now))
+	})
+
+	// Test custom time formats.
+	t.Run("time format", func(t *testing.T) {
+		entry := logger.Entry{Address: "127.0.0.1:16200", Request: "players", Response: "-admin", TimeFormat: "2006-01-02 15:04"}
+		assert.Equal(t, "[2023-03-11 12:00] 127.0.0.1:16200: players\n-admin\n\n", entry.Line(now))
+
+		entry.TimeFormat = logger.TimeFormatUnix
+		assert.Equal(t, "[1678536000] 127.0.0.1:16200: players\n-admin\n\n", entry.Line(now))
+
+		entry.TimeFormat = logger.TimeFormatUnixMilli
+		data, err := entry.JSON(now)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"time":"1678536000000","address":"127.0.0.1:16200","request":"players","response":"-admin"}`, string(data))
+	})
+
+	// Test command latency in text and JSON records.
+	t.Run("latency", func(t *testing.T) {
+		entry := logger.Entry{Address: "127.0.0.1:16200", Request: "players", Response: "-admin", Latency: 25 * time.Millisecond}
+		assert.Equal(t, "[2023-03-11T12:00:00Z] 127.0.0.1:16200: players (latency 25ms)\n-admin\n\n", entry.Line(now))
+
+		data, err := entry.JSON(now)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"time":"2023-03-11T12:00:00Z","address":"127.0.0.1:16200","request":"players","response":"-admin","latency_ms":25}`, string(data))
+
+		entry.Latency = time.Microsecond
+		data, err = entry.JSON(now)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"latency_ms":0}`)
+	})
+}
+
+func TestExpandSyslogTag(t *testing.T) {
+	assert.Equal(t, "rcon-cli", logger.ExpandSyslogTag(logger.SyslogTag, "prod"))
+	assert.Equal(t, "rcon-prod", logger.ExpandSyslogTag("rcon-%e", "prod"))
+}
+
+func TestTail(t *testing.T) {
+	logName := "tmpfile-tail.log"
+
+	defer os.Remove(logName)
+
+	// Test missing log file.
+	t.Run("file not exist", func(t *testing.T) {
+		_, err := logger.Tail(logName, 1)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	// Test text log with responses containing empty lines and records
+	// spread over several chunks.
+	t.Run("text log", func(t *testing.T) {
+		defer os.Remove(logName)
+
+		for i := 0; i < 200; i++ {
+			err := logger.Write(logName, logger.Entry{
+				Address: "127.0.0.1:16200", Request: fmt.Sprintf("players %d", i), Response: "Players:\n\n-admin",
+			})
+			assert.NoError(t, err)
+		}
+
+		entries, err := logger.Tail(logName, 2)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+		assert.Regexp(t, `^\[.+\] 127\.0\.0\.1:16200: players 198\nPlayers:\n\n-admin$`, entries[0])
+		assert.Regexp(t, `^\[.+\] 127\.0\.0\.1:16200: players 199\nPlayers:\n\n-admin$`, entries[1])
+
+		entries, err = logger.Tail(logName, 500)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 200)
+	})
+
+	// Test text log with Unix time and records of previous versions.
+	t.Run("time formats", func(t *testing.T) {
+		defer os.Remove(logName)
+
+		body := "[2023-03-11 12:00:00] 127.0.0.1:16200: help\n-help\n\n" +
+			"[1678536000] 127.0.0.1:16200: players\n-admin\n\n"
+		assert.NoError(t, os.WriteFile(logName, []byte(body), 0o600))
+
+		entries, err := logger.Tail(logName, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"[2023-03-11 12:00:00] 127.0.0.1:16200: help\n-help", "[1678536000] 127.0.0.1:16200: players\n-admin"}, entries)
+	})
+
+	// Test JSON lines log.
+	t.Run("json log", func(t *testing.T) {
+		defer os.Remove(logName)
+
+		body := "{\"request\":\"help\"}\n{\"request\":\"players\"}\n{\"request\":\"status\"}\n"
+		assert.NoError(t, os.WriteFile(logName, []byte(body), 0o600))
+
+		entries, err := logger.Tail(logName, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`{"request":"players"}`, `{"request":"status"}`}, entries)
+	})
+}
@@ -0,0 +1,71 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+	"time"
+)
+
+var syslogFacilities = map[string]syslog.Priority{
+	"kern": syslog.LOG_KERN, "user": syslog.LOG_USER, "mail": syslog.LOG_MAIL, "daemon": syslog.LOG_DAEMON,
+	"auth": syslog.LOG_AUTH, "syslog": syslog.LOG_SYSLOG, "lpr": syslog.LOG_LPR, "news": syslog.LOG_NEWS,
+	"uucp": syslog.LOG_UUCP, "cron": syslog.LOG_CRON, "authpriv": syslog.LOG_AUTHPRIV, "ftp": syslog.LOG_FTP,
+	"local0": syslog.LOG_LOCAL0, "local1": syslog.LOG_LOCAL1, "local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3, "local4": syslog.LOG_LOCAL4, "local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6, "local7": syslog.LOG_LOCAL7,
+}
+
+var syslogSeverities = map[string]syslog.Priority{
+	"emerg": syslog.LOG_EMERG, "alert": syslog.LOG_ALERT, "crit": syslog.LOG_CRIT, "err": syslog.LOG_ERR,
+	"warning": syslog.LOG_WARNING, "notice": syslog.LOG_NOTICE, "info": syslog.LOG_INFO, "debug": syslog.LOG_DEBUG,
+}
+
+// ValidateSyslog checks syslog facility and severity names.
+func ValidateSyslog(facility string, severity string) error {
+	_, err := syslogPriority(facility, severity)
+
+	return err
+}
+
+// WriteSyslog sends request and response to the local syslog daemon in the
+// text log format. SyslogTag is used if tag is empty.
+func WriteSyslog(facility string, severity string, tag string, entry Entry) error {
+	priority, err := syslogPriority(facility, severity)
+	if err != nil {
+		return err
+	}
+
+	if tag == "" {
+		tag = SyslogTag
+	}
+
+	w, err := syslog.New(priority, tag)
+	if err != nil {
+		return fmt.Errorf("syslog: %w", err)
+	}
+	defer w.Close()
+
+	if _, err = w.Write([]byte(strings.TrimRight(entry.Line(time.Now()), "\n"))); err != nil {
+		return fmt.Errorf("syslog: %w", err)
+	}
+
+	return nil
+}
+
+// syslogPriority returns priority by facility and severity names.
+func syslogPriority(facility string, severity string) (syslog.Priority, error) {
+	f, ok := syslogFacilities[strings.ToLower(facility)]
+	if !ok {
+		return 0, fmt.Errorf("%w facility %s", ErrSyslogUnsupported, facility)
+	}
+
+	s, ok := syslogSeverities[strings.ToLower(severity)]
+	if !ok {
+		return 0, fmt.Errorf("%w severity %s", ErrSyslogUnsupported, severity)
+	}
+
+	return f | s, nil
+}
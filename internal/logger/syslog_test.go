@@ -0,0 +1,22 @@
+//go:build !windows && !plan9
+
+package logger_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSyslog(t *testing.T) {
+	assert.NoError(t, logger.ValidateSyslog("user", "info"))
+	assert.NoError(t, logger.ValidateSyslog("LOCAL0", "Warning"))
+
+	err := logger.ValidateSyslog("pigeon", "info")
+	assert.EqualError(t, err, "unsupported syslog facility pigeon")
+
+	err = logger.ValidateSyslog("user", "loud")
+	assert.ErrorIs(t, err, logger.ErrSyslogUnsupported)
+	assert.EqualError(t, err, "unsupported syslog severity loud")
+}
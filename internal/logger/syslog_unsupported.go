@@ -0,0 +1,17 @@
+//go:build windows || plan9
+
+package logger
+
+import "fmt"
+
+// ValidateSyslog returns error because syslog is not available on this
+// platform.
+func ValidateSyslog(_ string, _ string) error {
+	return fmt.Errorf("%w on this platform", ErrSyslogUnsupported)
+}
+
+// WriteSyslog returns error because syslog is not available on this
+// platform.
+func WriteSyslog(_ string, _ string, _ string, _ Entry) error {
+	return fmt.Errorf("%w on this platform", ErrSyslogUnsupported)
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+)
+
+// TailChunkSize is the size of blocks which are read from the end of the log
+// file to find the last entries.
+const TailChunkSize = 4096
+
+// textEntryStart matches the beginning of the text log record. Records with
+// RFC3339 time, time without zone written by previous versions and Unix time
+// are recognized.
+var textEntryStart = regexp.MustCompile(`(?m)^\[(\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}:\d{2}[^\]\n]*|\d+)\] `)
+
+// Tail returns the last n entries from the log file. The file is read
+// backwards by TailChunkSize blocks until n entries are found. Text log
+// records and JSON lines are supported, the format is detected by the first
+// byte of the file.
+func Tail(name string, n int) ([]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat: %w", err)
+	}
+
+	if n <= 0 || info.Size() == 0 {
+		return nil, nil
+	}
+
+	first := make([]byte, 1)
+	if _, err = file.ReadAt(first, 0); err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+
+	starts := textStarts
+	if first[0] == '{' {
+		starts = jsonStarts
+	}
+
+	var buf []byte
+
+	for offset := info.Size(); ; {
+		size := int64(TailChunkSize)
+		if offset < size {
+			size = offset
+		}
+
+		offset -= size
+
+		chunk := make([]byte, size)
+		if _, err = file.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("read: %w", err)
+		}
+
+		buf = append(chunk, buf...)
+
+		positions := starts(buf, offset == 0)
+		if len(positions) >= n || offset == 0 {
+			if len(positions) > n {
+				positions = positions[len(positions)-n:]
+			}
+
+			return split(buf, positions), nil
+		}
+	}
+}
+
+// textStarts returns positions of text log records in buf. Record starts
+// after an empty line. The record at the beginning of buf is counted only if
+// buf is read from the beginning of the file.
+func textStarts(buf []byte, fromStart bool) []int {
+	var positions []int
+
+	for _, loc := range textEntryStart.FindAllIndex(buf, -1) {
+		pos := loc[0]
+
+		if (pos == 0 && fromStart) || (pos >= 2 && buf[pos-1] == '\n' && buf[pos-2] == '\n') {
+			positions = append(positions, pos)
+		}
+	}
+
+	return positions
+}
+
+// jsonStarts returns positions of JSON lines in buf.
+func jsonStarts(buf []byte, fromStart bool) []int {
+	var positions []int
+
+	for pos := 0; pos < len(buf); pos++ {
+		if buf[pos] != '{' {
+			continue
+		}
+
+		if (pos == 0 && fromStart) || (pos >= 1 && buf[pos-1] == '\n') {
+			positions = append(positions, pos)
+		}
+	}
+
+	return positions
+}
+
+// split returns entries of buf which start at positions without trailing
+// line breaks.
+func split(buf []byte, positions []int) []string {
+	entries := make([]string, 0, len(positions))
+
+	for i, pos := range positions {
+		end := len(buf)
+		if i+1 < len(positions) {
+			end = positions[i+1]
+		}
+
+		entries = append(entries, string(bytes.TrimRight(buf[pos:end], "\n")))
+	}
+
+	return entries
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// JSONEntry is the JSON representation of Entry which is sent to log
+// aggregators.
+type JSONEntry struct {
+	Time     string `json:"time"`
+	Address  string `json:"address"`
+	Request  string `json:"request"`
+	Response string `json:"response"`
+	Operator string `json:"operator,omitempty"`
+	// LatencyMS is nil if Entry Latency is zero, so that zero milliseconds
+	// of fast commands are still written.
+	LatencyMS *int64 `json:"latency_ms,omitempty"`
+}
+
+// JSON returns log record as JSON object.
+func (entry *Entry) JSON(now time.Time) ([]byte, error) {
+	record := JSONEntry{
+		Time:     entry.time(now),
+		Address:  entry.Address,
+		Request:  entry.Request,
+		Response: entry.Response,
+		Operator: entry.Operator,
+	}
+
+	if entry.Latency != 0 {
+		ms := entry.Latency.Milliseconds()
+		record.LatencyMS = &ms
+	}
+
+	return json.Marshal(record)
+}
+
+// ValidateUDP checks that UDP address of log aggregator can be resolved.
+func ValidateUDP(address string) error {
+	if _, err := net.ResolveUDPAddr("udp", address); err != nil {
+		return fmt.Errorf("udp: %w", err)
+	}
+
+	return nil
+}
+
+// WriteUDP sends log record as JSON datagram to address. Datagrams which
+// fail to send are dropped silently, only address resolution errors are
+// returned.
+func WriteUDP(address string, entry Entry) error {
+	// Disable logging if address is empty.
+	if address == "" {
+		return nil
+	}
+
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return fmt.Errorf("udp: %w", err)
+	}
+	defer conn.Close()
+
+	data, err := entry.JSON(time.Now())
+	if err != nil {
+		return fmt.Errorf("udp: %w", err)
+	}
+
+	_, _ = conn.Write(data)
+
+	return nil
+}
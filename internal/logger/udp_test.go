@@ -0,0 +1,52 @@
+package logger_test
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Test empty address.
+	t.Run("empty address", func(t *testing.T) {
+		assert.NoError(t, logger.WriteUDP("", logger.Entry{}))
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		assert.Error(t, logger.ValidateUDP("127.0.0.1"))
+		assert.Error(t, logger.WriteUDP("127.0.0.1", logger.Entry{}))
+	})
+
+	// Test entry is received as JSON datagram.
+	t.Run("send entry", func(t *testing.T) {
+		assert.NoError(t, logger.ValidateUDP(conn.LocalAddr().String()))
+
+		entry := logger.Entry{Address: "127.0.0.1:16260", Request: "players", Response: "Players connected (0):"}
+		assert.NoError(t, logger.WriteUDP(conn.LocalAddr().String(), entry))
+
+		buf := make([]byte, 1024)
+
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+		n, _, err := conn.ReadFrom(buf)
+		assert.NoError(t, err)
+
+		var received logger.JSONEntry
+		assert.NoError(t, json.Unmarshal(buf[:n], &received))
+		assert.Equal(t, entry.Address, received.Address)
+		assert.Equal(t, entry.Request, received.Request)
+		assert.Equal(t, entry.Response, received.Response)
+		assert.Empty(t, received.Operator)
+		assert.NotEmpty(t, received.Time)
+	})
+}
@@ -0,0 +1,195 @@
+// Package mux implements Source RCON Protocol client which allows to execute
+// commands concurrently over one TCP connection. Each command gets a unique
+// packet ID and the response is matched to the request by this ID.
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/internal/proto/source"
+)
+
+// DefaultDeadline provides default deadline to wait for response.
+const DefaultDeadline = 5 * time.Second
+
+// ErrConnClosed is returned when connection is closed while waiting for
+// response.
+var ErrConnClosed = errors.New("connection closed")
+
+// Conn is RCON connection which is safe for concurrent use.
+type Conn struct {
+	conn     net.Conn
+	deadline time.Duration
+
+	// lastID is the last used packet ID. IDs are started from 1 because
+	// SERVERDATA_AUTH_ID is 0.
+	lastID int32
+	// pending maps packet ID to the waiting request.
+	pending sync.Map
+	writeMu sync.Mutex
+
+	// mirrors maps packet ID of the empty SERVERDATA_RESPONSE_VALUE sent
+	// after the split response to the request packet ID. It is used by
+	// readLoop only.
+	mirrors map[int32]int32
+
+	done    chan struct{}
+	readErr error
+}
+
+// request collects response packets of the command.
+type request struct {
+	body     strings.Builder
+	mirrored bool
+	response chan string
+}
+
+// NewConn starts reading responses from conn which is already authorized by
+// source.Auth. Execute waits for response during deadline, zero disables it.
+func NewConn(conn net.Conn, deadline time.Duration) *Conn {
+	client := &Conn{conn: conn, deadline: deadline, mirrors: make(map[int32]int32), done: make(chan struct{})}
+
+	go client.readLoop()
+
+	return client
+}
+
+// Execute sends command to execute to the remote server and waits for the
+// response with the same packet ID. It is safe to call Execute from
+// multiple goroutines.
+func (c *Conn) Execute(command string) (string, error) {
+	if err := source.Validate(command); err != nil {
+		return "", err
+	}
+
+	id := c.nextID()
+	req := &request{response: make(chan string, 1)}
+
+	c.pending.Store(id, req)
+	defer c.pending.Delete(id)
+
+	if err := c.write(rcon.SERVERDATA_EXECCOMMAND, id, command); err != nil {
+		return "", fmt.Errorf("mux: %w", err)
+	}
+
+	var timeout <-chan time.Time
+
+	if c.deadline != 0 {
+		timer := time.NewTimer(c.deadline)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case response := <-req.response:
+		return response, nil
+	case <-c.done:
+		return "", fmt.Errorf("mux: %w: %v", ErrConnClosed, c.readErr)
+	case <-timeout:
+		return "", fmt.Errorf("mux: %w", os.ErrDeadlineExceeded)
+	}
+}
+
+// Close closes the connection. Pending Execute calls return ErrConnClosed.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// nextID returns unique packet ID for the connection.
+func (c *Conn) nextID() int32 {
+	for {
+		id := atomic.AddInt32(&c.lastID, 1)
+		if id > 0 {
+			return id
+		}
+
+		// Start over after overflow.
+		atomic.CompareAndSwapInt32(&c.lastID, id, 0)
+	}
+}
+
+// readLoop reads packets and passes responses to waiting Execute calls.
+// Packets with unknown IDs are ignored.
+//
+// Response which fills the whole packet may be continued in the next packets,
+// so an empty SERVERDATA_RESPONSE_VALUE is sent after it. Server mirrors it
+// after the rest of the response, and the collected body is passed when the
+// mirrored packet is received.
+func (c *Conn) readLoop() {
+	defer close(c.done)
+
+	for {
+		packet, err := source.Read(c.conn)
+		if err != nil {
+			c.readErr = err
+
+			return
+		}
+
+		if id, ok := c.mirrors[packet.ID]; ok {
+			delete(c.mirrors, packet.ID)
+			c.deliver(id)
+
+			continue
+		}
+
+		value, ok := c.pending.Load(packet.ID)
+		if !ok {
+			continue
+		}
+
+		req := value.(*request)
+		req.body.WriteString(packet.Body())
+
+		if len(packet.Body()) >= source.SplitBodyLen && !req.mirrored {
+			mirrorID := c.nextID()
+			c.mirrors[mirrorID] = packet.ID
+			req.mirrored = true
+
+			if err = c.write(rcon.SERVERDATA_RESPONSE_VALUE, mirrorID, ""); err != nil {
+				c.readErr = err
+
+				return
+			}
+
+			continue
+		}
+
+		if !req.mirrored {
+			c.deliver(packet.ID)
+		}
+	}
+}
+
+// deliver passes the collected response to the waiting Execute call.
+func (c *Conn) deliver(id int32) {
+	value, ok := c.pending.Load(id)
+	if !ok {
+		return
+	}
+
+	req := value.(*request)
+
+	select {
+	case req.response <- req.body.String():
+	default:
+	}
+}
+
+// write creates packet and writes it to established conn. Writes are
+// serialized to not interleave packets from different goroutines.
+func (c *Conn) write(packetType int32, packetID int32, body string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return source.Write(c.conn, packetType, packetID, body)
+}
@@ -0,0 +1,181 @@
+package mux_test
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/internal/mux"
+	"github.com/gorcon/rcon-cli/internal/proto/source"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+// dial connects to the server and authorizes the connection.
+func dial(t *testing.T, address string, deadline time.Duration) *mux.Conn {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = source.Auth(conn, "password", time.Second); err != nil {
+		conn.Close()
+		t.Fatal(err)
+	}
+
+	client := mux.NewConn(conn, deadline)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestConn_Execute(t *testing.T) {
+	server := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			// Write a packet for another request which must be ignored.
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, -2, "noise").WriteTo(c.Conn())
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, c.Request().Body()).WriteTo(c.Conn())
+		}),
+	)
+	t.Cleanup(server.Close)
+
+	conn := dial(t, server.Addr(), mux.DefaultDeadline)
+
+	t.Run("empty command", func(t *testing.T) {
+		_, err := conn.Execute("")
+		assert.ErrorIs(t, err, rcon.ErrCommandEmpty)
+	})
+
+	t.Run("concurrent commands", func(t *testing.T) {
+		const count = 20
+
+		var wg sync.WaitGroup
+
+		results := make([]string, count)
+
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				result, err := conn.Execute(fmt.Sprintf("echo %d", i))
+				assert.NoError(t, err)
+
+				results[i] = result
+			}(i)
+		}
+
+		wg.Wait()
+
+		for i, result := range results {
+			assert.Equal(t, fmt.Sprintf("echo %d", i), result)
+		}
+	})
+}
+
+func TestConn_Execute_OutOfOrder(t *testing.T) {
+	// Responses for two requests are written in reverse order.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	defer listener.Close()
+
+	go func() {
+		c, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer c.Close()
+
+		auth := &rcon.Packet{}
+		_, _ = auth.ReadFrom(c)
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_AUTH_RESPONSE, auth.ID, "").WriteTo(c)
+
+		first, second := &rcon.Packet{}, &rcon.Packet{}
+		_, _ = first.ReadFrom(c)
+		_, _ = second.ReadFrom(c)
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, second.ID, second.Body()).WriteTo(c)
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, first.ID, first.Body()).WriteTo(c)
+
+		time.Sleep(100 * time.Millisecond)
+	}()
+
+	conn := dial(t, listener.Addr().String(), mux.DefaultDeadline)
+
+	var wg sync.WaitGroup
+
+	for _, command := range []string{"first", "second"} {
+		wg.Add(1)
+
+		go func(command string) {
+			defer wg.Done()
+
+			result, err := conn.Execute(command)
+			assert.NoError(t, err)
+			assert.Equal(t, command, result)
+		}(command)
+	}
+
+	wg.Wait()
+}
+
+func TestConn_Execute_Split(t *testing.T) {
+	long := strings.Repeat("a", source.SplitBodyLen)
+
+	// Response is split into two packets which are followed by the mirrored
+	// empty packet and the extra packet some servers send after it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	defer listener.Close()
+
+	go func() {
+		c, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer c.Close()
+
+		auth := &rcon.Packet{}
+		_, _ = auth.ReadFrom(c)
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_AUTH_RESPONSE, auth.ID, "").WriteTo(c)
+
+		for {
+			request := &rcon.Packet{}
+			if _, err := request.ReadFrom(c); err != nil {
+				return
+			}
+
+			switch {
+			case request.Type == rcon.SERVERDATA_RESPONSE_VALUE:
+				_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "").WriteTo(c)
+				_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "\x00\x01").WriteTo(c)
+			case request.Body() == "long":
+				_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, long).WriteTo(c)
+				_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "tail").WriteTo(c)
+			default:
+				_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, request.Body()).WriteTo(c)
+			}
+		}
+	}()
+
+	conn := dial(t, listener.Addr().String(), time.Second)
+
+	result, err := conn.Execute("long")
+	assert.NoError(t, err)
+	assert.Equal(t, long+"tail", result)
+
+	result, err = conn.Execute("short")
+	assert.NoError(t, err)
+	assert.Equal(t, "short", result)
+}
@@ -0,0 +1,28 @@
+// Package path expands the user home directory in file paths.
+package path
+
+import (
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// HomePrefix is the prefix of paths relative to the user home directory.
+const HomePrefix = "~"
+
+// ExpandHome replaces a leading ~ in path with the current user home
+// directory. Paths like ~user are not supported and returned as is, as well
+// as path when the home directory cannot be determined.
+func ExpandHome(path string) string {
+	if path != HomePrefix && !strings.HasPrefix(path, HomePrefix+"/") &&
+		!strings.HasPrefix(path, HomePrefix+string(filepath.Separator)) {
+		return path
+	}
+
+	current, err := user.Current()
+	if err != nil || current.HomeDir == "" {
+		return path
+	}
+
+	return filepath.Join(current.HomeDir, path[len(HomePrefix):])
+}
@@ -0,0 +1,29 @@
+package path_test
+
+import (
+	"os/user"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/path"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandHome(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	for value, expected := range map[string]string{
+		"~":                   current.HomeDir,
+		"~/.rcon_history":     filepath.Join(current.HomeDir, ".rcon_history"),
+		"~/logs/rcon.log":     filepath.Join(current.HomeDir, "logs", "rcon.log"),
+		"~user/rcon.log":      "~user/rcon.log",
+		"/etc/rcon/rcon.yaml": "/etc/rcon/rcon.yaml",
+		"logs/~/rcon.log":     "logs/~/rcon.log",
+		"":                    "",
+	} {
+		assert.Equal(t, expected, path.ExpandHome(value), value)
+	}
+}
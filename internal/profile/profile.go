@@ -0,0 +1,129 @@
+// Package profile contains named shortcuts to config file and environment
+// pairs. Profiles are stored in a separate file in the user home directory
+// so that one profile can reference any config file.
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFileName is the name of profiles file in the user home directory.
+const DefaultFileName = ".rcon-profiles"
+
+var (
+	// ErrProfileNotFound is returned when profile is not defined in
+	// profiles file.
+	ErrProfileNotFound = errors.New("profile not found")
+
+	// ErrProfileExists is returned when profile is already defined in
+	// profiles file.
+	ErrProfileExists = errors.New("profile already exists")
+)
+
+// Profile points to environment in config file.
+type Profile struct {
+	Config string `yaml:"config"`
+	Env    string `yaml:"env"`
+}
+
+// Profiles maps profile names to profiles.
+type Profiles map[string]Profile
+
+// DefaultPath returns path to profiles file in the user home directory.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("get home dir: %w", err)
+	}
+
+	return filepath.Join(home, DefaultFileName), nil
+}
+
+// Load reads profiles file. Empty Profiles is returned if file does not exist.
+func Load(name string) (Profiles, error) {
+	profiles := make(Profiles)
+
+	file, err := os.ReadFile(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return profiles, nil
+		}
+
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(file, &profiles); err != nil {
+		return nil, fmt.Errorf("yaml: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// Save writes profiles to file.
+func (profiles Profiles) Save(name string) error {
+	file, err := yaml.Marshal(profiles)
+	if err != nil {
+		return fmt.Errorf("yaml: %w", err)
+	}
+
+	const perm = 0o600
+
+	if err := os.WriteFile(name, file, perm); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns profile by name.
+func (profiles Profiles) Get(name string) (Profile, error) {
+	profile, ok := profiles[name]
+	if !ok {
+		return profile, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	return profile, nil
+}
+
+// Add adds a new profile. Existing profile is not overwritten.
+func (profiles Profiles) Add(name string, profile Profile) error {
+	if _, ok := profiles[name]; ok {
+		return fmt.Errorf("%w: %s", ErrProfileExists, name)
+	}
+
+	profiles[name] = profile
+
+	return nil
+}
+
+// Remove removes profile by name.
+func (profiles Profiles) Remove(name string) error {
+	if _, ok := profiles[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	delete(profiles, name)
+
+	return nil
+}
+
+// Print writes sorted list of profiles to w.
+func (profiles Profiles) Print(w io.Writer) {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "%s: %s (%s)\n", name, profiles[name].Config, profiles[name].Env)
+	}
+}
@@ -0,0 +1,72 @@
+package profile_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/profile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("file not exist", func(t *testing.T) {
+		profiles, err := profile.Load(filepath.Join(t.TempDir(), profile.DefaultFileName))
+		assert.NoError(t, err)
+		assert.Empty(t, profiles)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), profile.DefaultFileName)
+		assert.NoError(t, os.WriteFile(name, []byte("{"), 0o600))
+
+		_, err := profile.Load(name)
+		assert.Error(t, err)
+	})
+
+	t.Run("save and load", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), profile.DefaultFileName)
+
+		profiles := profile.Profiles{"prod": {Config: "/etc/rcon/prod.yaml", Env: "rust"}}
+		assert.NoError(t, profiles.Save(name))
+
+		loaded, err := profile.Load(name)
+		assert.NoError(t, err)
+		assert.Equal(t, profiles, loaded)
+	})
+}
+
+func TestProfiles(t *testing.T) {
+	profiles := make(profile.Profiles)
+
+	// Test adding profiles.
+	t.Run("add", func(t *testing.T) {
+		assert.NoError(t, profiles.Add("prod", profile.Profile{Config: "prod.yaml", Env: "rust"}))
+		assert.NoError(t, profiles.Add("dev", profile.Profile{Config: "dev.yaml", Env: "default"}))
+
+		err := profiles.Add("prod", profile.Profile{Config: "other.yaml", Env: "rust"})
+		assert.ErrorIs(t, err, profile.ErrProfileExists)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		p, err := profiles.Get("prod")
+		assert.NoError(t, err)
+		assert.Equal(t, profile.Profile{Config: "prod.yaml", Env: "rust"}, p)
+
+		_, err = profiles.Get("stage")
+		assert.ErrorIs(t, err, profile.ErrProfileNotFound)
+	})
+
+	t.Run("print", func(t *testing.T) {
+		w := &bytes.Buffer{}
+		profiles.Print(w)
+		assert.Equal(t, "dev: dev.yaml (default)\nprod: prod.yaml (rust)\n", w.String())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		assert.NoError(t, profiles.Remove("dev"))
+		assert.ErrorIs(t, profiles.Remove("dev"), profile.ErrProfileNotFound)
+		assert.Len(t, profiles, 1)
+	})
+}
@@ -0,0 +1,280 @@
+// Package battleye implements BattlEye RCON Protocol over UDP which is used
+// by DayZ and Arma series servers.
+package battleye
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultDialTimeout provides default dial timeout to server.
+const DefaultDialTimeout = 5 * time.Second
+
+// DefaultDeadline provides default deadline to read/write operations.
+const DefaultDeadline = 5 * time.Second
+
+// DefaultKeepAlive is the default interval of keep alive packets. Servers
+// drop clients which send nothing for 45 seconds.
+const DefaultKeepAlive = 30 * time.Second
+
+// MaxPacketSize is the maximum size of the received UDP packet.
+const MaxPacketSize = 65507
+
+var (
+	// ErrAuthFailed is returned when server rejects the password.
+	ErrAuthFailed = errors.New("authentication failed")
+
+	// ErrCommandEmpty is returned when executed command is empty.
+	ErrCommandEmpty = errors.New("command too small to be executed")
+)
+
+// Settings contains option to Conn.
+type Settings struct {
+	dialTimeout time.Duration
+	deadline    time.Duration
+	keepAlive   time.Duration
+}
+
+// DefaultSettings provides default deadline settings to Conn.
+var DefaultSettings = Settings{
+	dialTimeout: DefaultDialTimeout,
+	deadline:    DefaultDeadline,
+	keepAlive:   DefaultKeepAlive,
+}
+
+// Option allows to inject settings to Settings.
+type Option func(s *Settings)
+
+// SetDialTimeout injects dial Timeout to Settings.
+func SetDialTimeout(timeout time.Duration) Option {
+	return func(s *Settings) {
+		s.dialTimeout = timeout
+	}
+}
+
+// SetDeadline injects read/write Timeout to Settings.
+func SetDeadline(timeout time.Duration) Option {
+	return func(s *Settings) {
+		s.deadline = timeout
+	}
+}
+
+// SetKeepAlive injects interval of keep alive packets to Settings. Zero
+// disables keep alive packets.
+func SetKeepAlive(interval time.Duration) Option {
+	return func(s *Settings) {
+		s.keepAlive = interval
+	}
+}
+
+// Conn is BattlEye RCON connection.
+type Conn struct {
+	conn     net.Conn
+	settings Settings
+
+	// mu guards conn and seq because keep alive packets are sent from
+	// background goroutine.
+	mu  sync.Mutex
+	seq byte
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Dial creates a new authorized Conn connection. Keep alive packets are sent
+// in background until the connection is closed.
+func Dial(address string, password string, options ...Option) (*Conn, error) {
+	settings := DefaultSettings
+
+	for _, option := range options {
+		option(&settings)
+	}
+
+	conn, err := net.DialTimeout("udp", address, settings.dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("battleye: %w", err)
+	}
+
+	client := Conn{conn: conn, settings: settings, done: make(chan struct{})}
+
+	if err := client.auth(password); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("battleye: %w", err)
+	}
+
+	if settings.keepAlive > 0 {
+		go client.keepAlive()
+	}
+
+	return &client, nil
+}
+
+// Execute sends command to execute to the remote server and returns the
+// response. Multi-packet responses are reassembled. Server messages which
+// are received meanwhile are acknowledged and skipped.
+func (c *Conn) Execute(command string) (string, error) {
+	if command == "" {
+		return "", ErrCommandEmpty
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.execute(command)
+}
+
+// Close stops keep alive packets and closes the connection.
+func (c *Conn) Close() error {
+	var err error
+
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.conn.Close()
+	})
+
+	return err
+}
+
+// auth sends login packet and checks the response.
+func (c *Conn) auth(password string) error {
+	if err := c.write(Packet{Type: PacketLogin, Payload: []byte(password)}); err != nil {
+		return err
+	}
+
+	for {
+		packet, err := c.read()
+		if err != nil {
+			return err
+		}
+
+		if packet.Type != PacketLogin || len(packet.Payload) == 0 {
+			continue
+		}
+
+		if packet.Payload[0] != 0x01 {
+			return ErrAuthFailed
+		}
+
+		return nil
+	}
+}
+
+// execute sends command packet with the next sequence number and waits for
+// the response with the same number.
+func (c *Conn) execute(command string) (string, error) {
+	seq := c.seq
+	c.seq++
+
+	if err := c.write(NewCommandPacket(seq, command)); err != nil {
+		return "", err
+	}
+
+	var parts [][]byte
+
+	received := 0
+
+	for {
+		packet, err := c.read()
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case packet.Type == PacketMessage && len(packet.Payload) != 0:
+			if err := c.write(Packet{Type: PacketMessage, Payload: packet.Payload[:1]}); err != nil {
+				return "", err
+			}
+
+			continue
+		case packet.Type != PacketCommand || len(packet.Payload) == 0 || packet.Payload[0] != seq:
+			continue
+		}
+
+		body := packet.Payload[1:]
+
+		// Multi-packet response has 0x00 header with packets count and index.
+		const multiHeaderSize = 3
+		if len(body) < multiHeaderSize || body[0] != 0x00 {
+			return string(body), nil
+		}
+
+		total, index := int(body[1]), int(body[2])
+		if parts == nil {
+			parts = make([][]byte, total)
+		}
+
+		if index < len(parts) && parts[index] == nil {
+			parts[index] = body[multiHeaderSize:]
+			received++
+		}
+
+		if received == len(parts) {
+			response := make([]byte, 0, len(parts)*len(body))
+			for _, part := range parts {
+				response = append(response, part...)
+			}
+
+			return string(response), nil
+		}
+	}
+}
+
+// keepAlive sends empty command packets with the keep alive interval.
+func (c *Conn) keepAlive() {
+	ticker := time.NewTicker(c.settings.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			_, _ = c.execute("")
+			c.mu.Unlock()
+		}
+	}
+}
+
+// write writes packet to established conn.
+func (c *Conn) write(packet Packet) error {
+	if c.settings.deadline != 0 {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(c.settings.deadline)); err != nil {
+			return err
+		}
+	}
+
+	_, err := c.conn.Write(packet.Marshal())
+
+	return err
+}
+
+// read reads packet from established conn. Packets with invalid header or
+// checksum are skipped.
+func (c *Conn) read() (Packet, error) {
+	if c.settings.deadline != 0 {
+		if err := c.conn.SetReadDeadline(time.Now().Add(c.settings.deadline)); err != nil {
+			return Packet{}, err
+		}
+	}
+
+	buf := make([]byte, MaxPacketSize)
+
+	for {
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			return Packet{}, err
+		}
+
+		packet, err := UnmarshalPacket(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		return packet, nil
+	}
+}
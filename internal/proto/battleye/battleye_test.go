@@ -0,0 +1,177 @@
+package battleye_test
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/proto/battleye"
+	"github.com/stretchr/testify/assert"
+)
+
+// server is a minimal BattlEye RCON server for tests.
+type server struct {
+	conn       net.PacketConn
+	password   string
+	keepAlives atomic.Int32
+	acks       atomic.Int32
+}
+
+func newServer(t *testing.T, password string) *server {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &server{conn: conn, password: password}
+
+	go s.serve()
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return s
+}
+
+func (s *server) Addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+func (s *server) serve() {
+	buf := make([]byte, battleye.MaxPacketSize)
+
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		packet, err := battleye.UnmarshalPacket(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		switch packet.Type {
+		case battleye.PacketLogin:
+			result := byte(0x00)
+			if string(packet.Payload) == s.password {
+				result = 0x01
+			}
+
+			s.send(addr, battleye.Packet{Type: battleye.PacketLogin, Payload: []byte{result}})
+		case battleye.PacketMessage:
+			s.acks.Add(1)
+		case battleye.PacketCommand:
+			s.command(addr, packet.Payload[0], string(packet.Payload[1:]))
+		}
+	}
+}
+
+func (s *server) command(addr net.Addr, seq byte, command string) {
+	response := func(body ...byte) battleye.Packet {
+		return battleye.Packet{Type: battleye.PacketCommand, Payload: append([]byte{seq}, body...)}
+	}
+
+	switch command {
+	case "":
+		s.keepAlives.Add(1)
+		s.send(addr, response())
+	case "players":
+		// Parts are sent in reverse order to check reassembly.
+		s.send(addr, response(append([]byte{0x00, 0x02, 0x01}, "-admin"...)...))
+		s.send(addr, response(append([]byte{0x00, 0x02, 0x00}, "Players:\n"...)...))
+	case "message":
+		s.send(addr, battleye.Packet{Type: battleye.PacketMessage, Payload: append([]byte{0x00}, "Player joined"...)})
+		s.send(addr, response([]byte("done")...))
+	default:
+		s.send(addr, response([]byte("Unknown command")...))
+	}
+}
+
+func (s *server) send(addr net.Addr, packet battleye.Packet) {
+	_, _ = s.conn.WriteTo(packet.Marshal(), addr)
+}
+
+func TestUnmarshalPacket(t *testing.T) {
+	packet := battleye.NewCommandPacket(0x05, "players")
+
+	data := packet.Marshal()
+	assert.Equal(t, []byte("BE"), data[:2])
+
+	parsed, err := battleye.UnmarshalPacket(data)
+	assert.NoError(t, err)
+	assert.Equal(t, packet, parsed)
+
+	data[len(data)-1] = 'X'
+	_, err = battleye.UnmarshalPacket(data)
+	assert.ErrorIs(t, err, battleye.ErrInvalidChecksum)
+
+	_, err = battleye.UnmarshalPacket([]byte("BE"))
+	assert.ErrorIs(t, err, battleye.ErrInvalidPacket)
+}
+
+func TestDial(t *testing.T) {
+	s := newServer(t, "password")
+
+	t.Run("auth failed", func(t *testing.T) {
+		conn, err := battleye.Dial(s.Addr(), "wrong")
+		assert.Nil(t, conn)
+		assert.ErrorIs(t, err, battleye.ErrAuthFailed)
+	})
+
+	t.Run("auth success", func(t *testing.T) {
+		conn, err := battleye.Dial(s.Addr(), "password")
+		assert.NoError(t, err)
+		assert.NoError(t, conn.Close())
+	})
+
+	t.Run("keep alive", func(t *testing.T) {
+		conn, err := battleye.Dial(s.Addr(), "password", battleye.SetKeepAlive(20*time.Millisecond))
+		assert.NoError(t, err)
+
+		defer conn.Close()
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Greater(t, s.keepAlives.Load(), int32(0))
+	})
+}
+
+func TestConn_Execute(t *testing.T) {
+	s := newServer(t, "password")
+
+	conn, err := battleye.Dial(s.Addr(), "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	t.Run("empty command", func(t *testing.T) {
+		_, err := conn.Execute("")
+		assert.ErrorIs(t, err, battleye.ErrCommandEmpty)
+	})
+
+	t.Run("single packet", func(t *testing.T) {
+		result, err := conn.Execute("help")
+		assert.NoError(t, err)
+		assert.Equal(t, "Unknown command", result)
+	})
+
+	// Test multi-packet response reassembly.
+	t.Run("multi packet", func(t *testing.T) {
+		result, err := conn.Execute("players")
+		assert.NoError(t, err)
+		assert.Equal(t, "Players:\n-admin", result)
+	})
+
+	// Test server message is acknowledged.
+	t.Run("server message", func(t *testing.T) {
+		result, err := conn.Execute("message")
+		assert.NoError(t, err)
+		assert.Equal(t, "done", result)
+
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, int32(1), s.acks.Load())
+	})
+}
@@ -0,0 +1,65 @@
+package battleye
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// Packet types.
+const (
+	PacketLogin   byte = 0x00
+	PacketCommand byte = 0x01
+	PacketMessage byte = 0x02
+)
+
+// headerSize is the size of "BE" magic, CRC32 checksum and 0xFF separator.
+const headerSize = 7
+
+var (
+	// ErrInvalidPacket is returned when packet has no BattlEye header or it
+	// is too short.
+	ErrInvalidPacket = errors.New("invalid packet")
+
+	// ErrInvalidChecksum is returned when packet CRC32 checksum does not
+	// match its payload.
+	ErrInvalidChecksum = errors.New("invalid packet checksum")
+)
+
+// Packet is BattlEye RCON packet. Payload contains everything after the
+// packet type: password for login, sequence number and command text for
+// command and message packets.
+type Packet struct {
+	Type    byte
+	Payload []byte
+}
+
+// NewCommandPacket creates command packet with sequence number. Empty command
+// is used as keep alive packet.
+func NewCommandPacket(seq byte, command string) Packet {
+	return Packet{Type: PacketCommand, Payload: append([]byte{seq}, command...)}
+}
+
+// Marshal returns packet bytes with "BE" header and CRC32 checksum.
+func (packet Packet) Marshal() []byte {
+	body := append([]byte{0xFF, packet.Type}, packet.Payload...)
+
+	data := make([]byte, headerSize-1, headerSize-1+len(body))
+	data[0], data[1] = 'B', 'E'
+	binary.LittleEndian.PutUint32(data[2:], crc32.ChecksumIEEE(body))
+
+	return append(data, body...)
+}
+
+// UnmarshalPacket parses packet bytes and checks CRC32 checksum.
+func UnmarshalPacket(data []byte) (Packet, error) {
+	if len(data) < headerSize+1 || data[0] != 'B' || data[1] != 'E' || data[headerSize-1] != 0xFF {
+		return Packet{}, ErrInvalidPacket
+	}
+
+	if binary.LittleEndian.Uint32(data[2:]) != crc32.ChecksumIEEE(data[headerSize-1:]) {
+		return Packet{}, ErrInvalidChecksum
+	}
+
+	return Packet{Type: data[headerSize], Payload: data[headerSize+1:]}, nil
+}
@@ -0,0 +1,123 @@
+package source
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon"
+)
+
+// mirrorID is the packet ID of the empty SERVERDATA_RESPONSE_VALUE which is
+// sent after the response of the full packet size.
+const mirrorID = rcon.SERVERDATA_EXECCOMMAND_ID + 1
+
+// Conn executes commands one by one over the authorized connection.
+type Conn struct {
+	conn     net.Conn
+	deadline time.Duration
+}
+
+// NewConn creates a new Conn. Read and write operations are limited by
+// deadline, zero disables it.
+func NewConn(conn net.Conn, deadline time.Duration) *Conn {
+	return &Conn{conn: conn, deadline: deadline}
+}
+
+// Execute sends command to execute to the remote server and returns
+// the response body. Response which fills the whole packet may be continued
+// in the next packets, so an empty SERVERDATA_RESPONSE_VALUE is sent after it
+// and the packets are collected until the server mirrors it.
+func (c *Conn) Execute(command string) (string, error) {
+	if err := Validate(command); err != nil {
+		return "", err
+	}
+
+	if err := c.write(rcon.SERVERDATA_EXECCOMMAND, rcon.SERVERDATA_EXECCOMMAND_ID, command); err != nil {
+		return "", err
+	}
+
+	response, err := c.read()
+
+	// Some servers send one more packet after the mirrored one, it may be
+	// received after the previous response is returned.
+	for err == nil && response.ID == mirrorID {
+		response, err = c.read()
+	}
+
+	if err != nil {
+		return response.Body(), err
+	}
+
+	if response.ID != rcon.SERVERDATA_EXECCOMMAND_ID {
+		return response.Body(), rcon.ErrInvalidPacketID
+	}
+
+	if len(response.Body()) < SplitBodyLen {
+		return response.Body(), nil
+	}
+
+	if err = c.write(rcon.SERVERDATA_RESPONSE_VALUE, mirrorID, ""); err != nil {
+		return response.Body(), err
+	}
+
+	body := strings.Builder{}
+	body.WriteString(response.Body())
+
+	for {
+		if response, err = c.read(); err != nil {
+			return body.String(), err
+		}
+
+		if response.ID == mirrorID {
+			return body.String(), nil
+		}
+
+		body.WriteString(response.Body())
+	}
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// write creates packet and writes it to established conn.
+func (c *Conn) write(packetType int32, packetID int32, body string) error {
+	if c.deadline != 0 {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(c.deadline)); err != nil {
+			return fmt.Errorf("rcon: %w", err)
+		}
+	}
+
+	return Write(c.conn, packetType, packetID, body)
+}
+
+// read reads the response packet from established conn.
+func (c *Conn) read() (*rcon.Packet, error) {
+	if c.deadline != 0 {
+		if err := c.conn.SetReadDeadline(time.Now().Add(c.deadline)); err != nil {
+			return &rcon.Packet{}, fmt.Errorf("rcon: %w", err)
+		}
+	}
+
+	packet, err := Read(c.conn)
+	if err != nil {
+		return packet, err
+	}
+
+	// Rust server sends undocumented packet of type 4 before the valid one.
+	// Response of "say" command is sent with ID -1.
+	if packet.Type == 4 {
+		if packet, err = Read(c.conn); err != nil {
+			return packet, err
+		}
+
+		if packet.ID == -1 {
+			packet.ID = rcon.SERVERDATA_EXECCOMMAND_ID
+		}
+	}
+
+	return packet, nil
+}
@@ -0,0 +1,106 @@
+package source_test
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/internal/proto/source"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+// newUnixServer starts test server on Unix domain socket. Command "long"
+// is answered with the response split into two packets.
+func newUnixServer(t *testing.T, password string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rcon.sock")
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := rcontest.NewUnstartedServer(
+		rcontest.SetSettings(rcontest.Settings{Password: password}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			if c.Request().Body() != "long" {
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "Can I help you?").WriteTo(c.Conn())
+
+				return
+			}
+
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, strings.Repeat("a", source.SplitBodyLen)).WriteTo(c.Conn())
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "tail").WriteTo(c.Conn())
+
+			// Test server ignores SERVERDATA_RESPONSE_VALUE requests, so
+			// the mirrored packet and the extra one are sent here.
+			mirror := &rcon.Packet{}
+			_, _ = mirror.ReadFrom(c.Conn())
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, mirror.ID, "").WriteTo(c.Conn())
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, mirror.ID, "\x00\x01").WriteTo(c.Conn())
+		}),
+	)
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return path
+}
+
+// dialUnix connects to the socket file.
+func dialUnix(t *testing.T, path string) net.Conn {
+	t.Helper()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestConn_Execute(t *testing.T) {
+	// Test unix socket connection without authentication.
+	t.Run("no auth", func(t *testing.T) {
+		conn := source.NewConn(dialUnix(t, newUnixServer(t, "")), time.Second)
+		defer conn.Close()
+
+		_, err := conn.Execute("")
+		assert.ErrorIs(t, err, rcon.ErrCommandEmpty)
+
+		result, err := conn.Execute("help")
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?", result)
+	})
+
+	// Test split response is collected until the mirrored packet.
+	t.Run("split response", func(t *testing.T) {
+		path := newUnixServer(t, "password")
+
+		assert.ErrorIs(t, source.Auth(dialUnix(t, path), "wrong", time.Second), rcon.ErrAuthFailed)
+
+		c := dialUnix(t, path)
+		if !assert.NoError(t, source.Auth(c, "password", time.Second)) {
+			return
+		}
+
+		conn := source.NewConn(c, time.Second)
+		defer conn.Close()
+
+		result, err := conn.Execute("long")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", source.SplitBodyLen)+"tail", result)
+
+		result, err = conn.Execute("help")
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?", result)
+	})
+}
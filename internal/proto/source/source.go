@@ -0,0 +1,90 @@
+// Package source implements requests of Source RCON Protocol over
+// established connections. It is shared by the clients which own their
+// connections: stream, Unix domain socket and multiplexed clients.
+package source
+
+import (
+	"net"
+	"time"
+
+	"github.com/gorcon/rcon"
+)
+
+// SplitBodyLen is the minimum body length of the packet which may be followed
+// by the rest of the response. Servers split long responses into packets of
+// 4096 bytes with the same ID, some of them count the packet header in the
+// size.
+const SplitBodyLen = 4096 - int(rcon.MinPacketSize)
+
+// Auth sends SERVERDATA_AUTH request and checks SERVERDATA_AUTH_RESPONSE.
+// Deadline of conn is set to timeout during the request if it is not zero.
+func Auth(conn net.Conn, password string, timeout time.Duration) error {
+	if timeout != 0 {
+		if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+
+		defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	}
+
+	if err := Write(conn, rcon.SERVERDATA_AUTH, rcon.SERVERDATA_AUTH_ID, password); err != nil {
+		return err
+	}
+
+	response, err := Read(conn)
+	if err != nil {
+		return err
+	}
+
+	// Some servers send an empty SERVERDATA_RESPONSE_VALUE before
+	// SERVERDATA_AUTH_RESPONSE.
+	if response.Type == rcon.SERVERDATA_RESPONSE_VALUE {
+		if response, err = Read(conn); err != nil {
+			return err
+		}
+	}
+
+	if response.Type != rcon.SERVERDATA_AUTH_RESPONSE {
+		return rcon.ErrInvalidAuthResponse
+	}
+
+	if response.ID == -1 {
+		return rcon.ErrAuthFailed
+	}
+
+	if response.ID != rcon.SERVERDATA_AUTH_ID {
+		return rcon.ErrInvalidPacketID
+	}
+
+	return nil
+}
+
+// Validate checks command length.
+func Validate(command string) error {
+	if command == "" {
+		return rcon.ErrCommandEmpty
+	}
+
+	if len(command) > rcon.MaxCommandLen {
+		return rcon.ErrCommandTooLong
+	}
+
+	return nil
+}
+
+// Write creates packet and writes it to conn.
+func Write(conn net.Conn, packetType int32, packetID int32, body string) error {
+	_, err := rcon.NewPacket(packetType, packetID, body).WriteTo(conn)
+
+	return err
+}
+
+// Read reads packet from conn.
+func Read(conn net.Conn) (*rcon.Packet, error) {
+	packet := &rcon.Packet{}
+	if _, err := packet.ReadFrom(conn); err != nil {
+		return packet, err
+	}
+
+	return packet, nil
+}
@@ -0,0 +1,47 @@
+package source_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/internal/proto/source"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuth(t *testing.T) {
+	server := rcontest.NewServer(rcontest.SetSettings(rcontest.Settings{Password: "password"}))
+	defer server.Close()
+
+	// dial connects to the test server.
+	dial := func(t *testing.T) net.Conn {
+		t.Helper()
+
+		conn, err := net.Dial("tcp", server.Addr())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		return conn
+	}
+
+	t.Run("authentication failed", func(t *testing.T) {
+		err := source.Auth(dial(t), "wrong", time.Second)
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+	})
+
+	t.Run("auth success", func(t *testing.T) {
+		err := source.Auth(dial(t), "password", time.Second)
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	assert.ErrorIs(t, source.Validate(""), rcon.ErrCommandEmpty)
+	assert.ErrorIs(t, source.Validate(string(make([]byte, rcon.MaxCommandLen+1))), rcon.ErrCommandTooLong)
+	assert.NoError(t, source.Validate("status"))
+}
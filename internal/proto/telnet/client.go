@@ -0,0 +1,203 @@
+package telnet
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxCommandLen is an artificial restriction, but it will help in case of
+// random large queries.
+const MaxCommandLen = 1000
+
+// DefaultExitCommand is the command which ends the session on the server.
+const DefaultExitCommand = "exit"
+
+// ForcedExitCommand is replaced with DefaultExitCommand in Interactive.
+const ForcedExitCommand = ":q"
+
+// CRLF terminates sent commands.
+const CRLF = "\r\n"
+
+// ExecuteTickTimeout is the time to wait for the command response. Telnet
+// responses have no terminator, so everything received during the time is
+// the response.
+const ExecuteTickTimeout = 1 * time.Second
+
+// ReceiveWaitPeriod is the time to receive the remaining data before the
+// connection is closed.
+const ReceiveWaitPeriod = 3 * time.Millisecond
+
+// Remote server response messages.
+const (
+	ResponseAuthSuccess           = "Logon successful."
+	ResponseAuthIncorrectPassword = "Password incorrect, please enter password:"
+)
+
+var (
+	// ErrAuthFailed is returned when the server rejected sent password.
+	ErrAuthFailed = errors.New("authentication failed")
+
+	// ErrAuthUnexpectedMessage is returned when the server responses without
+	// ResponseAuthSuccess or ResponseAuthIncorrectPassword on auth request.
+	ErrAuthUnexpectedMessage = errors.New("unexpected authentication response")
+
+	// ErrCommandTooLong is returned when executed command length is bigger
+	// than MaxCommandLen characters.
+	ErrCommandTooLong = errors.New("command too long")
+
+	// ErrCommandEmpty is returned when executed command length equal 0.
+	ErrCommandEmpty = errors.New("command too small")
+)
+
+// Client executes commands on 7 Days to Die telnet server over the
+// negotiated Conn.
+type Client struct {
+	conn *Conn
+
+	// mu guards buffer which is filled by readLoop.
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+// NewClient sends password to the server over conn and starts reading
+// responses. The conn is closed if authentication failed.
+func NewClient(conn *Conn, password string) (*Client, error) {
+	client := Client{conn: conn}
+
+	go client.readLoop()
+
+	status, err := client.execute(password)
+	if err == nil {
+		switch {
+		case strings.Contains(status, ResponseAuthIncorrectPassword):
+			err = ErrAuthFailed
+		case !strings.Contains(status, ResponseAuthSuccess):
+			err = ErrAuthUnexpectedMessage
+		}
+	}
+
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// Execute sends command to execute to the server and returns data received
+// during ExecuteTickTimeout.
+func (c *Client) Execute(command string) (string, error) {
+	if command == "" {
+		return "", ErrCommandEmpty
+	}
+
+	return c.execute(command)
+}
+
+// Close sends exit command to the server and closes the connection.
+func (c *Client) Close() error {
+	_, _ = c.conn.Write([]byte(DefaultExitCommand + CRLF))
+
+	time.Sleep(ReceiveWaitPeriod)
+
+	return c.conn.Close()
+}
+
+// execute sends command and collects the response.
+func (c *Client) execute(command string) (string, error) {
+	if len(command) > MaxCommandLen {
+		return "", ErrCommandTooLong
+	}
+
+	if _, err := c.conn.Write([]byte(command + CRLF)); err != nil {
+		return "", err
+	}
+
+	time.Sleep(ExecuteTickTimeout)
+
+	c.mu.Lock()
+	response := c.buffer.String()
+	c.buffer.Reset()
+	c.mu.Unlock()
+
+	response = strings.ReplaceAll(response, "\x00", "")
+
+	return strings.TrimSpace(response), nil
+}
+
+// readLoop appends received data to the buffer until the connection is
+// closed.
+func (c *Client) readLoop() {
+	p := make([]byte, 4096)
+
+	for {
+		n, err := c.conn.Read(p)
+		if n > 0 {
+			c.mu.Lock()
+			c.buffer.Write(p[:n])
+			c.mu.Unlock()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Interactive sends lines from r to the server over conn and writes
+// received data to w until exit command is sent or r is ended. Password is
+// sent first if it is not empty, otherwise it is expected to be typed by
+// user.
+func Interactive(r io.Reader, w io.Writer, conn *Conn, password string) error {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, _ = io.Copy(w, conn)
+	}()
+
+	err := interactive(r, conn, password)
+
+	time.Sleep(ReceiveWaitPeriod)
+
+	_ = conn.Close()
+	<-done
+
+	return err
+}
+
+// interactive writes password and commands from r to conn.
+func interactive(r io.Reader, conn *Conn, password string) error {
+	if password != "" {
+		if _, err := conn.Write([]byte(password + CRLF)); err != nil {
+			return err
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		command := scanner.Text()
+		if command == ForcedExitCommand {
+			command = DefaultExitCommand
+		}
+
+		if _, err := conn.Write([]byte(command + CRLF)); err != nil {
+			return err
+		}
+
+		if command == DefaultExitCommand {
+			return nil
+		}
+	}
+
+	_, err := conn.Write([]byte(DefaultExitCommand + CRLF))
+
+	return err
+}
@@ -0,0 +1,104 @@
+package telnet_test
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/proto/telnet"
+	"github.com/gorcon/telnet/telnettest"
+	"github.com/stretchr/testify/assert"
+)
+
+// hello greets the command sender.
+func hello(c *telnettest.Context) {
+	if c.Request() != telnet.DefaultExitCommand {
+		_, _ = c.Writer().WriteString("Hello, " + c.Request() + telnet.CRLF)
+	}
+
+	_ = c.Writer().Flush()
+}
+
+// slowReader returns lines one by one with delay, so responses are
+// received before the next line is sent.
+type slowReader struct {
+	lines []string
+	delay time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(r.lines) == 0 {
+		return 0, io.EOF
+	}
+
+	time.Sleep(r.delay)
+
+	n := copy(p, r.lines[0]+"\n")
+	r.lines = r.lines[1:]
+
+	return n, nil
+}
+
+// negotiate connects to the server and answers option requests.
+func negotiate(t *testing.T, address string) *telnet.Conn {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	negotiated := telnet.NewConn(conn)
+	if err = negotiated.Negotiate(50 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	return negotiated
+}
+
+func TestNewClient(t *testing.T) {
+	server := telnettest.NewServer(
+		telnettest.SetSettings(telnettest.Settings{Password: "password"}),
+		telnettest.SetCommandHandler(hello),
+	)
+	defer server.Close()
+
+	// Test command is executed after authentication.
+	t.Run("success", func(t *testing.T) {
+		client, err := telnet.NewClient(negotiate(t, server.Addr()), "password")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer client.Close()
+
+		response, err := client.Execute("admin")
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello, admin", response)
+
+		_, err = client.Execute("")
+		assert.ErrorIs(t, err, telnet.ErrCommandEmpty)
+	})
+
+	// Test wrong password.
+	t.Run("auth failed", func(t *testing.T) {
+		_, err := telnet.NewClient(negotiate(t, server.Addr()), "wrong")
+		assert.ErrorIs(t, err, telnet.ErrAuthFailed)
+	})
+}
+
+func TestInteractive(t *testing.T) {
+	server := telnettest.NewServer(
+		telnettest.SetSettings(telnettest.Settings{Password: "password"}),
+		telnettest.SetCommandHandler(hello),
+	)
+	defer server.Close()
+
+	r := &slowReader{lines: []string{"admin", telnet.ForcedExitCommand}, delay: 100 * time.Millisecond}
+	w := &bytes.Buffer{}
+
+	err := telnet.Interactive(r, w, negotiate(t, server.Addr()), "password")
+	assert.NoError(t, err)
+	assert.Contains(t, w.String(), "Hello, admin")
+}
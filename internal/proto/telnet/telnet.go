@@ -0,0 +1,195 @@
+// Package telnet implements telnet option negotiation (RFC 854) and the
+// 7 Days to Die telnet client over the negotiated connections. Client
+// refuses all options: WILL requests are answered with DONT and DO requests
+// with WONT. IAC sequences are removed from received data and IAC bytes of
+// sent data are escaped.
+package telnet
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Telnet commands.
+const (
+	IAC  byte = 255
+	DONT byte = 254
+	DO   byte = 253
+	WONT byte = 252
+	WILL byte = 251
+	SB   byte = 250
+	SE   byte = 240
+)
+
+// DefaultNegotiationTimeout is the time to wait for option requests which
+// server sends after connection.
+const DefaultNegotiationTimeout = 200 * time.Millisecond
+
+// Conn is the telnet connection which handles option negotiation.
+type Conn struct {
+	net.Conn
+	r *bufio.Reader
+
+	// mu guards writes of negotiation replies from Read and writes of data.
+	mu sync.Mutex
+}
+
+// NewConn creates a new Conn.
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{Conn: conn, r: bufio.NewReader(conn)}
+}
+
+// Negotiate answers option requests which server sends after connection
+// before any data. It returns when data is received or timeout expires
+// without data, so password can be sent to the server after negotiation.
+func (c *Conn) Negotiate(timeout time.Duration) error {
+	if err := c.Conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return fmt.Errorf("telnet: %w", err)
+	}
+
+	for {
+		next, err := c.r.Peek(1)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("telnet: %w", err)
+		}
+
+		if next[0] != IAC {
+			break
+		}
+
+		_, _ = c.r.ReadByte()
+
+		if _, err = c.command(); err != nil {
+			return fmt.Errorf("telnet: %w", err)
+		}
+	}
+
+	if err := c.Conn.SetReadDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("telnet: %w", err)
+	}
+
+	return nil
+}
+
+// Read reads data without IAC sequences. Option requests are answered while
+// reading.
+func (c *Conn) Read(p []byte) (int, error) {
+	n := 0
+
+	for n < len(p) {
+		// Return received data instead of waiting for more.
+		if n > 0 && c.r.Buffered() == 0 {
+			break
+		}
+
+		b, err := c.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				break
+			}
+
+			return 0, err
+		}
+
+		if b == IAC {
+			literal, err := c.command()
+			if err != nil {
+				return n, err
+			}
+
+			if !literal {
+				continue
+			}
+		}
+
+		p[n] = b
+		n++
+	}
+
+	return n, nil
+}
+
+// Write writes p with escaped IAC bytes.
+func (c *Conn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.Conn.Write(bytes.ReplaceAll(p, []byte{IAC}, []byte{IAC, IAC})); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// command reads the command after IAC and answers option requests. It
+// returns true if the command is escaped IAC data byte. DONT and WONT are
+// not answered because all options are already disabled.
+func (c *Conn) command() (bool, error) {
+	cmd, err := c.r.ReadByte()
+	if err != nil {
+		return false, err
+	}
+
+	switch cmd {
+	case IAC:
+		return true, nil
+	case WILL, WONT, DO, DONT:
+		option, err := c.r.ReadByte()
+		if err != nil {
+			return false, err
+		}
+
+		switch cmd {
+		case WILL:
+			return false, c.reply(DONT, option)
+		case DO:
+			return false, c.reply(WONT, option)
+		}
+	case SB:
+		return false, c.skipSubnegotiation()
+	}
+
+	return false, nil
+}
+
+// reply sends the answer to the option request.
+func (c *Conn) reply(cmd byte, option byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.Conn.Write([]byte{IAC, cmd, option})
+
+	return err
+}
+
+// skipSubnegotiation reads data until IAC SE.
+func (c *Conn) skipSubnegotiation() error {
+	for {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		if b != IAC {
+			continue
+		}
+
+		if b, err = c.r.ReadByte(); err != nil {
+			return err
+		}
+
+		if b == SE {
+			return nil
+		}
+	}
+}
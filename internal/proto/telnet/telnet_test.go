@@ -0,0 +1,116 @@
+package telnet_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/proto/telnet"
+	"github.com/stretchr/testify/assert"
+)
+
+// dial returns client and server sides of TCP connection.
+func dial(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	return client, server
+}
+
+func TestConn_Negotiate(t *testing.T) {
+	const (
+		optionEcho         = 1
+		optionTerminalType = 24
+	)
+
+	// Test option requests are refused before password prompt.
+	t.Run("refuse options", func(t *testing.T) {
+		client, server := dial(t)
+
+		_, _ = server.Write([]byte{
+			telnet.IAC, telnet.DO, optionTerminalType, telnet.IAC, telnet.WILL, optionEcho,
+			telnet.IAC, telnet.WONT, optionEcho, telnet.IAC, telnet.SB, optionTerminalType, 1, telnet.IAC, telnet.SE,
+		})
+		_, _ = server.Write([]byte("Please enter password:\r\n"))
+
+		conn := telnet.NewConn(client)
+		assert.NoError(t, conn.Negotiate(time.Second))
+
+		replies := make([]byte, 6)
+		_, err := io.ReadFull(server, replies)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{telnet.IAC, telnet.WONT, optionTerminalType, telnet.IAC, telnet.DONT, optionEcho}, replies)
+
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "Please enter password:\r\n", line)
+	})
+
+	// Test server which sends nothing after connection.
+	t.Run("timeout", func(t *testing.T) {
+		client, server := dial(t)
+
+		conn := telnet.NewConn(client)
+		assert.NoError(t, conn.Negotiate(50*time.Millisecond))
+
+		_, _ = server.Write([]byte("Logon successful.\r\n"))
+
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "Logon successful.\r\n", line)
+	})
+}
+
+func TestConn_Read(t *testing.T) {
+	client, server := dial(t)
+
+	conn := telnet.NewConn(client)
+
+	_, _ = server.Write([]byte{'a', telnet.IAC, telnet.IAC, 'b', telnet.IAC, telnet.DO, 3, 'c', '\n'})
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "a\xffbc\n", line)
+
+	reply := make([]byte, 3)
+	_, err = io.ReadFull(server, reply)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{telnet.IAC, telnet.WONT, 3}, reply)
+}
+
+func TestConn_Write(t *testing.T) {
+	client, server := dial(t)
+
+	conn := telnet.NewConn(client)
+
+	n, err := conn.Write([]byte("say \xff\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, n)
+
+	data := make([]byte, 8)
+	_, err = io.ReadFull(server, data)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("say \xff\xff\r\n"), data)
+}
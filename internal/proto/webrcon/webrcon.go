@@ -0,0 +1,120 @@
+// Package webrcon implements Rust Web RCON client over established
+// connections. Commands are sent in JSON messages over WebSocket and
+// responses are matched to them by identifier.
+package webrcon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorcon/websocket"
+	gorilla "github.com/gorilla/websocket"
+)
+
+// Conn is Web RCON connection.
+type Conn struct {
+	conn     *gorilla.Conn
+	deadline time.Duration
+
+	// lastID is the last used message identifier.
+	lastID atomic.Int32
+}
+
+// Dial performs WebSocket handshake over conn during timeout and returns the
+// authorized connection. Password is sent in the URL path and address in the
+// Host header. Execute waits for response during deadline, zero disables it.
+func Dial(conn net.Conn, address string, password string, timeout time.Duration, deadline time.Duration) (*Conn, error) {
+	dialer := gorilla.Dialer{
+		NetDialContext: func(context.Context, string, string) (net.Conn, error) {
+			return conn, nil
+		},
+		HandshakeTimeout: timeout,
+	}
+
+	u := url.URL{Scheme: "ws", Host: address, Path: password}
+
+	ws, _, err := dialer.Dial(u.String(), nil)
+	if err != nil {
+		_ = conn.Close()
+
+		// Server closes the connection with close frame instead of HTTP
+		// response if password is wrong.
+		if err.Error() == `malformed HTTP response "\x88\x02\x03\xe8"` {
+			return nil, websocket.ErrAuthFailed
+		}
+
+		return nil, fmt.Errorf("webrcon: %w", err)
+	}
+
+	return &Conn{conn: ws, deadline: deadline}, nil
+}
+
+// Execute sends command string to execute to the remote server and waits for
+// the response with the same identifier.
+func (c *Conn) Execute(command string) (string, error) {
+	if command == "" {
+		return "", websocket.ErrCommandEmpty
+	}
+
+	if len(command) > websocket.MaxCommandLen {
+		return "", websocket.ErrCommandTooLong
+	}
+
+	request := websocket.Message{Message: command, Identifier: int(c.lastID.Add(1))}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("webrcon: %w", err)
+	}
+
+	if err = c.setDeadline(c.conn.SetWriteDeadline); err != nil {
+		return "", err
+	}
+
+	if err = c.conn.WriteMessage(gorilla.TextMessage, data); err != nil {
+		return "", fmt.Errorf("webrcon: %w", err)
+	}
+
+	for {
+		if err = c.setDeadline(c.conn.SetReadDeadline); err != nil {
+			return "", err
+		}
+
+		_, p, err := c.conn.ReadMessage()
+		if err != nil {
+			return "", fmt.Errorf("webrcon: %w", err)
+		}
+
+		var response websocket.Message
+		if err = json.Unmarshal(p, &response); err != nil {
+			return "", fmt.Errorf("webrcon: %w", err)
+		}
+
+		if response.Identifier == request.Identifier {
+			return response.Message, nil
+		}
+	}
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// setDeadline sets read or write deadline if it is not disabled.
+func (c *Conn) setDeadline(set func(t time.Time) error) error {
+	if c.deadline == 0 {
+		return nil
+	}
+
+	if err := set(time.Now().Add(c.deadline)); err != nil {
+		return fmt.Errorf("webrcon: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package webrcon_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/proto/webrcon"
+	"github.com/gorcon/websocket"
+	gorilla "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// newServer starts Web RCON server which answers each command with its text
+// after a message with another identifier.
+func newServer(t *testing.T) string {
+	t.Helper()
+
+	upgrader := gorilla.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/password" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		defer ws.Close()
+
+		for {
+			var request websocket.Message
+			if err := ws.ReadJSON(&request); err != nil {
+				return
+			}
+
+			_ = ws.WriteJSON(websocket.Message{Message: "log line", Identifier: -1, Type: "Generic"})
+			_ = ws.WriteJSON(websocket.Message{Message: request.Message, Identifier: request.Identifier, Type: "Generic"})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server.Listener.Addr().String()
+}
+
+// dial connects to the server.
+func dial(t *testing.T, address string) net.Conn {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+func TestDial(t *testing.T) {
+	address := newServer(t)
+
+	// Test handshake with wrong password.
+	t.Run("wrong path", func(t *testing.T) {
+		_, err := webrcon.Dial(dial(t, address), address, "wrong", time.Second, time.Second)
+		assert.ErrorContains(t, err, "webrcon: websocket: bad handshake")
+	})
+
+	// Test responses are matched to commands by identifier.
+	t.Run("success", func(t *testing.T) {
+		conn, err := webrcon.Dial(dial(t, address), address, "password", time.Second, time.Second)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		_, err = conn.Execute("")
+		assert.ErrorIs(t, err, websocket.ErrCommandEmpty)
+
+		for _, command := range []string{"status", "players"} {
+			response, err := conn.Execute(command)
+			assert.NoError(t, err)
+			assert.Equal(t, command, response)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// Package rewrite rewrites commands before sending by expressions of Expr
+// language (https://expr-lang.org).
+//
+// The command is passed in the command variable and the expression must
+// return a string, for example `"say " + upper(trim(command))` or
+// `command startsWith "kick " ? "say kicked" : command`. All builtin
+// functions, operators and conditionals of the language are available.
+package rewrite
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/file"
+	"github.com/expr-lang/expr/vm"
+)
+
+// CommandVariable is the name of the variable which contains the command.
+const CommandVariable = "command"
+
+// ErrInvalidExpression is returned when expression can not be compiled.
+var ErrInvalidExpression = errors.New("invalid expression")
+
+// Expr is a compiled expression. It is safe for concurrent use.
+type Expr struct {
+	source  string
+	program *vm.Program
+}
+
+// Compile parses the expression and checks it returns a string.
+func Compile(source string) (*Expr, error) {
+	program, err := expr.Compile(source, expr.Env(map[string]any{CommandVariable: ""}), expr.AsKind(reflect.String))
+	if err != nil {
+		var fileErr *file.Error
+		if errors.As(err, &fileErr) {
+			return nil, fmt.Errorf("%w %q: %s", ErrInvalidExpression, source, fileErr.Message)
+		}
+
+		return nil, fmt.Errorf("%w %q: %s", ErrInvalidExpression, source, err)
+	}
+
+	return &Expr{source: source, program: program}, nil
+}
+
+// String returns the source expression.
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Eval returns the rewritten command.
+func (e *Expr) Eval(command string) (string, error) {
+	result, err := expr.Run(e.program, map[string]any{CommandVariable: command})
+	if err != nil {
+		return "", fmt.Errorf("rewrite: %w", err)
+	}
+
+	// Compile checks the expression kind, nil is returned for nil values
+	// only.
+	rewritten, _ := result.(string)
+
+	return rewritten, nil
+}
@@ -0,0 +1,50 @@
+package rewrite_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/rewrite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	for _, source := range []string{
+		"", "cmd", "upper(", "upper(command", "upper(command, command)", "unknown(command)",
+		`"say`, "command +", "command command", "len(command)", `command == "help"`,
+	} {
+		_, err := rewrite.Compile(source)
+		assert.ErrorIs(t, err, rewrite.ErrInvalidExpression, source)
+	}
+}
+
+func TestExpr_Eval(t *testing.T) {
+	for source, expected := range map[string]string{
+		"command":                             " Kick Player ",
+		"upper(command)":                      " KICK PLAYER ",
+		"lower(trim(command))":                "kick player",
+		`"say " + trim(command) + '!'`:        "say Kick Player!",
+		`replace(trim(command), " ", "_")`:    "Kick_Player",
+		`trimPrefix(trim(command), "Kick ")`:  "Player",
+		`trimSuffix(trim(command), "Player")`: "Kick ",
+		`"\t"`:                                "\t",
+		`trim(command) startsWith "Kick" ? "say kicked" : command`: "say kicked",
+		`len(command) > 20 ? "" : split(trim(command), " ")[1]`:    "Player",
+	} {
+		expr, err := rewrite.Compile(source)
+		if !assert.NoError(t, err, source) {
+			continue
+		}
+
+		result, err := expr.Eval(" Kick Player ")
+		assert.NoError(t, err, source)
+		assert.Equal(t, expected, result, source)
+		assert.Equal(t, source, expr.String())
+	}
+
+	// Test runtime error.
+	expr, err := rewrite.Compile(`split(command, " ")[5]`)
+	assert.NoError(t, err)
+
+	_, err = expr.Eval("kick")
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+// Package script runs Tengo scripts to automate RCON sessions.
+//
+// Scripts call rcon.execute(command) to send the command to the remote
+// server, it returns the response string. Printing functions of fmt module
+// write to the script output. Standard modules text, math, times, rand, json,
+// base64, hex and enum are available, os module is not, so scripts have no
+// access to the file system, processes and environment.
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/d5/tengo/v2"
+	"github.com/d5/tengo/v2/stdlib"
+)
+
+// DefaultTimeout limits script run time to stop scripts which never exit.
+const DefaultTimeout = 10 * time.Minute
+
+// Modules are the standard modules which can be imported by scripts.
+var Modules = []string{"text", "math", "times", "rand", "json", "base64", "hex", "enum"}
+
+// ErrTimeout is returned when script is not finished in time.
+var ErrTimeout = errors.New("script timed out")
+
+// Executor sends commands to the remote server.
+type Executor interface {
+	Execute(command string) (string, error)
+}
+
+// Run compiles and runs the script source. Output of fmt functions is written
+// to w. The script is stopped after timeout if it is positive.
+func Run(w io.Writer, executor Executor, source []byte, timeout time.Duration) error {
+	s := tengo.NewScript(source)
+
+	modules := stdlib.GetModuleMap(Modules...)
+	modules.AddBuiltinModule("fmt", fmtModule(w))
+	s.SetImports(modules)
+
+	if err := s.Add("rcon", rconModule(executor)); err != nil {
+		return err
+	}
+
+	compiled, err := s.Compile()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if err = compiled.RunContext(ctx); errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+
+	return err
+}
+
+// rconModule returns rcon object with execute function.
+func rconModule(executor Executor) *tengo.ImmutableMap {
+	execute := func(args ...tengo.Object) (tengo.Object, error) {
+		if len(args) != 1 {
+			return nil, tengo.ErrWrongNumArguments
+		}
+
+		command, ok := args[0].(*tengo.String)
+		if !ok {
+			return nil, tengo.ErrInvalidArgumentType{Name: "command", Expected: "string", Found: args[0].TypeName()}
+		}
+
+		response, err := executor.Execute(command.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return &tengo.String{Value: response}, nil
+	}
+
+	return &tengo.ImmutableMap{Value: map[string]tengo.Object{
+		"execute": &tengo.UserFunction{Name: "execute", Value: execute},
+	}}
+}
+
+// fmtModule returns fmt module of Tengo standard library which prints to w
+// instead of stdout.
+func fmtModule(w io.Writer) map[string]tengo.Object {
+	printer := func(newline bool) tengo.CallableFunc {
+		return func(args ...tengo.Object) (tengo.Object, error) {
+			values := make([]interface{}, len(args))
+			for i, arg := range args {
+				values[i], _ = tengo.ToString(arg)
+			}
+
+			if newline {
+				_, _ = fmt.Fprintln(w, values...)
+			} else {
+				_, _ = fmt.Fprint(w, values...)
+			}
+
+			return nil, nil
+		}
+	}
+
+	printf := func(args ...tengo.Object) (tengo.Object, error) {
+		if len(args) == 0 {
+			return nil, tengo.ErrWrongNumArguments
+		}
+
+		format, ok := args[0].(*tengo.String)
+		if !ok {
+			return nil, tengo.ErrInvalidArgumentType{Name: "format", Expected: "string", Found: args[0].TypeName()}
+		}
+
+		s, err := tengo.Format(format.Value, args[1:]...)
+		if err != nil {
+			return nil, err
+		}
+
+		_, _ = fmt.Fprint(w, s)
+
+		return nil, nil
+	}
+
+	return map[string]tengo.Object{
+		"print":   &tengo.UserFunction{Name: "print", Value: printer(false)},
+		"println": &tengo.UserFunction{Name: "println", Value: printer(true)},
+		"printf":  &tengo.UserFunction{Name: "printf", Value: printf},
+		"sprintf": stdlib.BuiltinModules["fmt"]["sprintf"],
+	}
+}
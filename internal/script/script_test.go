@@ -0,0 +1,85 @@
+package script_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/script"
+	"github.com/stretchr/testify/assert"
+)
+
+// executor records executed commands and responds with the number and the
+// command. Command "fail" returns an error.
+type executor struct {
+	commands []string
+}
+
+func (e *executor) Execute(command string) (string, error) {
+	e.commands = append(e.commands, command)
+
+	if command == "fail" {
+		return "", errors.New("command failed")
+	}
+
+	return fmt.Sprintf("%d %s", len(e.commands), command), nil
+}
+
+func TestRun(t *testing.T) {
+	// Test loops, conditions and standard modules.
+	t.Run("success", func(t *testing.T) {
+		source := `
+fmt := import("fmt")
+text := import("text")
+
+for name in ["admin", "player"] {
+	response := rcon.execute("kick " + name)
+	if text.has_prefix(response, "2 ") {
+		fmt.println("second: ", text.trim_prefix(response, "2 "))
+	} else {
+		fmt.printf("%s\n", text.to_upper(response))
+	}
+}
+`
+		w := &bytes.Buffer{}
+		e := &executor{}
+
+		err := script.Run(w, e, []byte(source), time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"kick admin", "kick player"}, e.commands)
+		assert.Equal(t, "1 KICK ADMIN\nsecond:  kick player\n", w.String())
+	})
+
+	// Test os module is not available.
+	t.Run("sandbox", func(t *testing.T) {
+		err := script.Run(&bytes.Buffer{}, &executor{}, []byte(`os := import("os")`), time.Second)
+		assert.ErrorContains(t, err, "module 'os' not found")
+	})
+
+	// Test command error stops the script.
+	t.Run("execute error", func(t *testing.T) {
+		w := &bytes.Buffer{}
+		e := &executor{}
+
+		err := script.Run(w, e, []byte(`rcon.execute("fail"); rcon.execute("help")`), time.Second)
+		assert.ErrorContains(t, err, "command failed")
+		assert.Equal(t, []string{"fail"}, e.commands)
+
+		err = script.Run(w, e, []byte(`rcon.execute(1)`), time.Second)
+		assert.ErrorContains(t, err, "invalid type for argument 'command'")
+	})
+
+	// Test infinite loop is stopped by timeout.
+	t.Run("timeout", func(t *testing.T) {
+		err := script.Run(&bytes.Buffer{}, &executor{}, []byte(`for {}`), 50*time.Millisecond)
+		assert.ErrorIs(t, err, script.ErrTimeout)
+	})
+
+	// Test compile error.
+	t.Run("syntax error", func(t *testing.T) {
+		err := script.Run(&bytes.Buffer{}, &executor{}, []byte(`if {`), time.Second)
+		assert.ErrorContains(t, err, "Parse Error")
+	})
+}
@@ -0,0 +1,118 @@
+// Package stream reads console output which is pushed by RCON server after
+// authentication, similar to `tail -f`.
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/proto/source"
+)
+
+// DefaultAuthTimeout provides default auth timeout to remote server.
+const DefaultAuthTimeout = 5 * time.Second
+
+// Settings contains option to Stream.
+type Settings struct {
+	authTimeout time.Duration
+	idleTimeout time.Duration
+	filter      *regexp.Regexp
+}
+
+// DefaultSettings provides default settings to Stream.
+var DefaultSettings = Settings{
+	authTimeout: DefaultAuthTimeout,
+}
+
+// Option allows to inject settings to Settings.
+type Option func(s *Settings)
+
+// SetAuthTimeout injects auth timeout to Settings.
+func SetAuthTimeout(timeout time.Duration) Option {
+	return func(s *Settings) {
+		s.authTimeout = timeout
+	}
+}
+
+// SetIdleTimeout injects idle timeout to Settings. Stream is stopped without
+// error if no lines were received during the timeout. Zero disables it.
+func SetIdleTimeout(timeout time.Duration) Option {
+	return func(s *Settings) {
+		s.idleTimeout = timeout
+	}
+}
+
+// SetFilter injects lines filter to Settings. Only lines which match the
+// filter are written.
+func SetFilter(filter *regexp.Regexp) Option {
+	return func(s *Settings) {
+		s.filter = filter
+	}
+}
+
+// Stream authenticates on conn to the remote server and writes received
+// console lines to w until the connection is closed or idle timeout expires.
+// The conn is closed on return.
+func Stream(conn net.Conn, password string, w io.Writer, options ...Option) error {
+	settings := DefaultSettings
+
+	for _, option := range options {
+		option(&settings)
+	}
+
+	defer conn.Close()
+
+	err := source.Auth(conn, password, settings.authTimeout)
+	if err != nil {
+		return fmt.Errorf("stream: %w", err)
+	}
+
+	for {
+		deadline := time.Time{}
+		if settings.idleTimeout != 0 {
+			deadline = time.Now().Add(settings.idleTimeout)
+		}
+
+		if err = conn.SetReadDeadline(deadline); err != nil {
+			return fmt.Errorf("stream: %w", err)
+		}
+
+		packet, err := source.Read(conn)
+		if err != nil {
+			if isStopped(err) {
+				return nil
+			}
+
+			return fmt.Errorf("stream: %w", err)
+		}
+
+		write(w, packet.Body(), settings.filter)
+	}
+}
+
+// write writes non-empty lines of body which match the filter to w.
+func write(w io.Writer, body string, filter *regexp.Regexp) {
+	for _, line := range strings.Split(strings.TrimRight(body, "\r\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || (filter != nil && !filter.MatchString(line)) {
+			continue
+		}
+
+		_, _ = fmt.Fprintln(w, line)
+	}
+}
+
+// isStopped checks whether the stream is closed by server or idle timeout.
+func isStopped(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, io.EOF)
+}
@@ -0,0 +1,108 @@
+package stream_test
+
+import (
+	"bytes"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/internal/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+// newServer starts server which writes lines to the client after successful
+// authentication and closes connection if closeConn is true.
+func newServer(t *testing.T, password string, lines []string, closeConn bool) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		request := rcon.Packet{}
+		if _, err := request.ReadFrom(conn); err != nil {
+			return
+		}
+
+		if request.Body() != password {
+			rcon.NewPacket(rcon.SERVERDATA_AUTH_RESPONSE, -1, "").WriteTo(conn)
+			return
+		}
+
+		rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "").WriteTo(conn)
+		rcon.NewPacket(rcon.SERVERDATA_AUTH_RESPONSE, rcon.SERVERDATA_AUTH_ID, "").WriteTo(conn)
+
+		for _, line := range lines {
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, 0, line).WriteTo(conn)
+		}
+
+		if !closeConn {
+			time.Sleep(time.Second)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// dial connects to the server.
+func dial(t *testing.T, address string) net.Conn {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+func TestStream(t *testing.T) {
+	lines := []string{"Player admin joined", "Server saved\nPlayer admin left\n"}
+
+	t.Run("no errors", func(t *testing.T) {
+		addr := newServer(t, "password", lines, true)
+
+		w := bytes.Buffer{}
+		err := stream.Stream(dial(t, addr), "password", &w)
+		assert.NoError(t, err)
+		assert.Equal(t, "Player admin joined\nServer saved\nPlayer admin left\n", w.String())
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		addr := newServer(t, "password", lines, true)
+
+		w := bytes.Buffer{}
+		err := stream.Stream(dial(t, addr), "password", &w, stream.SetFilter(regexp.MustCompile("^Player")))
+		assert.NoError(t, err)
+		assert.Equal(t, "Player admin joined\nPlayer admin left\n", w.String())
+	})
+
+	t.Run("idle timeout", func(t *testing.T) {
+		addr := newServer(t, "password", lines[:1], false)
+
+		w := bytes.Buffer{}
+		err := stream.Stream(dial(t, addr), "password", &w, stream.SetIdleTimeout(100*time.Millisecond))
+		assert.NoError(t, err)
+		assert.Equal(t, "Player admin joined\n", w.String())
+	})
+
+	t.Run("authentication failed", func(t *testing.T) {
+		addr := newServer(t, "password", lines, true)
+
+		w := bytes.Buffer{}
+		err := stream.Stream(dial(t, addr), "wrong", &w)
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+	})
+}
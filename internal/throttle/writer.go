@@ -0,0 +1,63 @@
+// Package throttle limits the rate of output lines.
+package throttle
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// Writer writes lines to the underlying writer not faster than the rate.
+// Data without line break is written immediately.
+type Writer struct {
+	w        io.Writer
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewWriter creates a new Writer for linesPerSecond rate.
+func NewWriter(w io.Writer, linesPerSecond float64) *Writer {
+	return &Writer{w: w, interval: time.Duration(float64(time.Second) / linesPerSecond)}
+}
+
+// Write writes p line by line and sleeps before each line if the rate is
+// exceeded.
+func (t *Writer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	written := 0
+
+	for len(p) > 0 {
+		line := p
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			line = p[:i+1]
+			t.wait()
+		}
+
+		n, err := t.w.Write(line)
+		written += n
+
+		if err != nil {
+			return written, err
+		}
+
+		p = p[len(line):]
+	}
+
+	return written, nil
+}
+
+// wait sleeps until the next line is allowed.
+func (t *Writer) wait() {
+	now := time.Now()
+	if now.Before(t.next) {
+		time.Sleep(t.next.Sub(now))
+		now = t.next
+	}
+
+	t.next = now.Add(t.interval)
+}
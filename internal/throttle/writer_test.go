@@ -0,0 +1,48 @@
+package throttle_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/throttle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_Write(t *testing.T) {
+	t.Run("rate is limited", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		w := throttle.NewWriter(&buf, 20)
+
+		start := time.Now()
+
+		n, err := w.Write([]byte("line 1\nline 2\nline 3\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 21, n)
+
+		_, err = w.Write([]byte("line 4\n"))
+		assert.NoError(t, err)
+
+		// First line is written immediately, three next wait 50ms each.
+		assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+		assert.Equal(t, "line 1\nline 2\nline 3\nline 4\n", buf.String())
+	})
+
+	t.Run("partial line is not throttled", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		w := throttle.NewWriter(&buf, 1)
+
+		start := time.Now()
+
+		_, err := w.Write([]byte("> "))
+		assert.NoError(t, err)
+
+		_, err = w.Write([]byte("> "))
+		assert.NoError(t, err)
+
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, "> > ", buf.String())
+	})
+}
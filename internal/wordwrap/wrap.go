@@ -0,0 +1,63 @@
+// Package wordwrap wraps text at word boundaries.
+package wordwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gorcon/rcon-cli/internal/ansi"
+)
+
+// Wrap wraps lines of s which are longer than width runes at spaces. Lines
+// which fit are kept as is, spaces between words of wrapped lines are
+// collapsed and leading indentation is kept on the first line. Words longer
+// than width are not broken and take a line of their own. ANSI escape
+// sequences are not counted in the width.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if Width(line) > width {
+			lines[i] = wrapLine(line, width)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Width returns the number of runes in s without ANSI escape sequences.
+func Width(s string) int {
+	return utf8.RuneCountInString(ansi.Strip(s))
+}
+
+// wrapLine splits a single line into lines not longer than width.
+func wrapLine(line string, width int) string {
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+
+	var b strings.Builder
+
+	b.WriteString(indent)
+	length := Width(indent)
+
+	for i, word := range strings.Fields(line) {
+		size := Width(word)
+
+		if i > 0 {
+			if length+1+size > width {
+				b.WriteByte('\n')
+				length = 0
+			} else {
+				b.WriteByte(' ')
+				length++
+			}
+		}
+
+		b.WriteString(word)
+		length += size
+	}
+
+	return b.String()
+}
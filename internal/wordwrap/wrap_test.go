@@ -0,0 +1,29 @@
+package wordwrap_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/wordwrap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	t.Run("short lines", func(t *testing.T) {
+		assert.Equal(t, "Players  connected\n\n(2)", wordwrap.Wrap("Players  connected\n\n(2)", 20))
+		assert.Equal(t, "Players connected", wordwrap.Wrap("Players connected", 0))
+	})
+
+	t.Run("long lines", func(t *testing.T) {
+		assert.Equal(t, "The quick\nbrown fox\njumps\nshort\nover the\nlazy dog",
+			wordwrap.Wrap("The quick brown fox jumps\nshort\nover   the lazy dog", 10))
+	})
+
+	t.Run("indent and long word", func(t *testing.T) {
+		assert.Equal(t, "  id\nabcdefghijkl\nend", wordwrap.Wrap("  id abcdefghijkl end", 8))
+	})
+
+	t.Run("ansi and unicode", func(t *testing.T) {
+		assert.Equal(t, "\x1b[32mпривет\x1b[0m мир", wordwrap.Wrap("\x1b[32mпривет\x1b[0m мир", 10))
+		assert.Equal(t, "\x1b[32mпривет\x1b[0m\nмир", wordwrap.Wrap("\x1b[32mпривет\x1b[0m мир", 8))
+	})
+}